@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	//_ "orderService/docs"
 	"os"
 	"os/signal"
 	"service/internal"
+	"service/internal/configs"
+	"service/pkg/requestid"
+	"service/pkg/tracing"
 	"syscall"
 
 	"github.com/sirupsen/logrus"
@@ -23,6 +27,23 @@ func main() {
 	log := logrus.New()
 	log.SetFormatter(&logrus.JSONFormatter{})
 	log.SetLevel(logrus.InfoLevel)
+	log.AddHook(tracing.LogrusHook{})
+	log.AddHook(requestid.LogrusHook{})
+
+	// Конфиг: значения по умолчанию, YAML файл, переменные окружения и флаги
+	cnf, opts, err := configs.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if opts.PrintConfig {
+		out, err := cnf.Redacted().YAML()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(out)
+		return
+	}
 
 	// Контекст для запуска сервера
 	ctx := context.Background()
@@ -32,7 +53,7 @@ func main() {
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL, syscall.SIGQUIT)
 
 	// Запускаем сервер
-	server, err := internal.NewServer(ctx, log)
+	server, err := internal.NewServer(ctx, cnf, log)
 	if err != nil {
 		log.Fatal(err)
 	}
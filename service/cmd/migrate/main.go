@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"service/internal/configs"
+	"service/pkg/db"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/pressly/goose/v3"
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `Usage: migrate [flags] <command> [version]
+
+Commands:
+  up [version]    применить все миграции или до указанной версии
+  down [version]  откатить последнюю миграцию или до указанной версии
+  redo            откатить и заново применить последнюю миграцию
+  status          показать состояние всех миграций
+  version         показать текущую версию базы
+
+Флаги те же, что у сервиса: --config, --db-host, --db-dsn и т.д.`
+
+func main() {
+	log := logrus.New()
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.SetLevel(logrus.InfoLevel)
+
+	cnf, opts, err := configs.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cnf.Storage != configs.StorageDatabase {
+		log.Fatalf("migrate works only with %s storage, got %q", configs.StorageDatabase, cnf.Storage)
+	}
+
+	if len(opts.Args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.Open(ctx, cnf.Database, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	migrator, err := db.NewMigrator(database, cnf.Database.Driver)
+	if err != nil {
+		database.Close()
+		log.Fatal(err)
+	}
+	defer migrator.Close()
+
+	if err = run(ctx, migrator, opts.Args[0], opts.Args[1:]); err != nil {
+		log.WithField("command", opts.Args[0]).Error(err)
+		migrator.Close()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, migrator *goose.Provider, command string, args []string) error {
+	switch command {
+	case "up":
+		if len(args) == 0 {
+			results, err := migrator.Up(ctx)
+			printResults(results)
+			return err
+		}
+		version, err := parseVersion(args[0])
+		if err != nil {
+			return err
+		}
+		results, err := migrator.UpTo(ctx, version)
+		printResults(results)
+		return err
+	case "down":
+		if len(args) == 0 {
+			result, err := migrator.Down(ctx)
+			printResults([]*goose.MigrationResult{result})
+			return err
+		}
+		version, err := parseVersion(args[0])
+		if err != nil {
+			return err
+		}
+		results, err := migrator.DownTo(ctx, version)
+		printResults(results)
+		return err
+	case "redo":
+		result, err := migrator.Down(ctx)
+		printResults([]*goose.MigrationResult{result})
+		if err != nil {
+			return err
+		}
+		result, err = migrator.UpByOne(ctx)
+		printResults([]*goose.MigrationResult{result})
+		return err
+	case "status":
+		return printStatus(ctx, migrator)
+	case "version":
+		current, target, err := migrator.GetVersions(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("current: %d\nlatest:  %d\n", current, target)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+}
+
+func parseVersion(raw string) (int64, error) {
+	version, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || version < 0 {
+		return 0, errors.New("version must be a non-negative number")
+	}
+	return version, nil
+}
+
+func printResults(results []*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		status := "OK"
+		if result.Error != nil {
+			status = "FAIL"
+		}
+		fmt.Printf("%-4s %-4s %d %s (%s)\n", status, result.Direction, result.Source.Version, result.Source.Path, result.Duration)
+	}
+}
+
+func printStatus(ctx context.Context, migrator *goose.Provider) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tFILE")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Source.Version, status.State, appliedAt, status.Source.Path)
+	}
+
+	return w.Flush()
+}
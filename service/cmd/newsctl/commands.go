@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"service/internal/models"
+	"service/internal/newsio"
+	"service/internal/service"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type command func(ctx context.Context, s service.INewsService, args []string) error
+
+var commands = map[string]command{
+	"list":   list,
+	"get":    get,
+	"create": create,
+	"edit":   edit,
+	"delete": remove,
+	"import": importNews,
+	"export": exportNews,
+	"tags":   tags,
+}
+
+// mediaCommands - команды, которым нужен каталог вложений
+var mediaCommands = map[string]bool{
+	"delete": true,
+}
+
+func list(ctx context.Context, s service.INewsService, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	limit := fs.Int64("limit", 20, "number of news")
+	offset := fs.Int64("offset", 0, "number of news to skip")
+	var filter models.NewsFilter
+	fs.Func("tags", "only news with all of these tags, comma separated", func(raw string) error {
+		for _, tag := range parseTags(raw) {
+			if err := models.ValidateTag(tag); err != nil {
+				return err
+			}
+			// Повтор тега сломал бы подсчёт совпадений в запросе
+			if !slices.Contains(filter.Tags, tag) {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+		return nil
+	})
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	newsList, err := s.ListNews(ctx, *limit, *offset, filter)
+	if err != nil {
+		return err
+	}
+
+	return printNewsList(os.Stdout, *output, newsList)
+}
+
+func get(ctx context.Context, s service.INewsService, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	output := outputFlag(fs)
+	id, err := parseWithID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	news, err := s.GetNews(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return printNews(os.Stdout, *output, news)
+}
+
+func create(ctx context.Context, s service.INewsService, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	title := fs.String("title", "", "news title")
+	content := fs.String("content", "", "news content")
+	contentFormat := fs.String("content-format", "", "content format: plain, markdown or html (default plain)")
+	categories := fs.String("categories", "", "comma separated category ids")
+	tagList := fs.String("tags", "", "comma separated tags")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	form := models.NewsCreateForm{Title: *title, Content: *content, ContentFormat: *contentFormat, Tags: parseTags(*tagList)}
+	if *categories != "" {
+		ids, err := parseIDs(*categories)
+		if err != nil {
+			return err
+		}
+		form.Categories = &ids
+	}
+
+	form.Normalize()
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	id, err := s.CreateNews(ctx, form)
+	if err != nil {
+		return err
+	}
+
+	return printResult(os.Stdout, *output, map[string]interface{}{"Success": true, "Id": id}, fmt.Sprintf("news %d created", id))
+}
+
+func edit(ctx context.Context, s service.INewsService, args []string) error {
+	fs := flag.NewFlagSet("edit", flag.ContinueOnError)
+	var form models.NewsEditForm
+	fs.Func("title", "new title", func(v string) error {
+		form.Title = &v
+		return nil
+	})
+	fs.Func("content", "new content", func(v string) error {
+		form.Content = &v
+		return nil
+	})
+	fs.Func("content-format", "new content format: plain, markdown or html", func(v string) error {
+		form.ContentFormat = &v
+		return nil
+	})
+	fs.Func("categories", "replace categories, comma separated ids", idsFlag(&form.Categories))
+	fs.Func("add-categories", "categories to add, comma separated ids", idsFlag(&form.AddCategories))
+	fs.Func("remove-categories", "categories to remove, comma separated ids", idsFlag(&form.RemoveCategories))
+	fs.Func("tags", "replace tags, comma separated, empty to remove all", func(raw string) error {
+		tags := parseTags(raw)
+		form.Tags = &tags
+		return nil
+	})
+	output := outputFlag(fs)
+	id, err := parseWithID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	form.Normalize()
+	if err = form.Validate(); err != nil {
+		return err
+	}
+
+	if err = s.EditNews(ctx, id, form); err != nil {
+		return err
+	}
+
+	return printResult(os.Stdout, *output, map[string]interface{}{"Success": true}, fmt.Sprintf("news %d updated", id))
+}
+
+func remove(ctx context.Context, s service.INewsService, args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	output := outputFlag(fs)
+	id, err := parseWithID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if err = s.DeleteNews(ctx, id); err != nil {
+		return err
+	}
+
+	return printResult(os.Stdout, *output, map[string]interface{}{"Success": true}, fmt.Sprintf("news %d deleted", id))
+}
+
+// importNews загружает новости из JSON Lines или CSV так же, как POST /admin/import:
+// строки с внешним id обновляют ранее загруженные новости, невалидные строки попадают в отчёт
+func importNews(ctx context.Context, s service.INewsService, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := fs.String("file", "-", "input file, - for stdin")
+	format := fs.String("format", newsio.FormatJSONL, "input format: jsonl or csv")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	in, err := openInput(*file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	report, err := newsio.Import(ctx, in, *format, *dryRun, s.ImportNews)
+	if err != nil {
+		return err
+	}
+
+	return printImportReport(os.Stdout, *output, report)
+}
+
+// exportNews выгружает все новости в JSON Lines или CSV так же, как GET /admin/export
+func exportNews(ctx context.Context, s service.INewsService, args []string) (err error) {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	file := fs.String("file", "-", "output file, - for stdout")
+	format := fs.String("format", newsio.FormatJSONL, "output format: jsonl or csv")
+	if err = fs.Parse(args); err != nil {
+		return err
+	}
+
+	out, err := openOutput(*file)
+	if err != nil {
+		return err
+	}
+	// Ошибка записи на диск может проявиться только при закрытии файла
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	encoder, err := newsio.NewEncoder(out, *format)
+	if err != nil {
+		return err
+	}
+
+	if err = s.ExportNews(ctx, encoder.Encode); err != nil {
+		return err
+	}
+
+	return encoder.Flush()
+}
+
+// tags подсказывает теги по началу, как GET /tags
+func tags(ctx context.Context, s service.INewsService, args []string) error {
+	fs := flag.NewFlagSet("tags", flag.ContinueOnError)
+	prefix := fs.String("prefix", "", "tag prefix")
+	limit := fs.Int64("limit", 20, "number of tags")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tagList, err := s.ListTags(ctx, models.NormalizeTag(*prefix), *limit)
+	if err != nil {
+		return err
+	}
+
+	return printTags(os.Stdout, *output, tagList)
+}
+
+func parseWithID(fs *flag.FlagSet, args []string) (int64, error) {
+	// id может стоять как до, так и после флагов команды
+	var id string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		id, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 0, err
+	}
+	if id == "" && fs.NArg() > 0 {
+		id = fs.Arg(0)
+	}
+	if id == "" {
+		return 0, errors.New("news id is required")
+	}
+
+	newsId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid news id %q", id)
+	}
+	return newsId, nil
+}
+
+func parseIDs(raw string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid category id %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseTags разбирает теги через запятую, пустая строка - пустой список
+func parseTags(raw string) []string {
+	tags := []string{}
+	for _, part := range strings.Split(raw, ",") {
+		if tag := models.NormalizeTag(part); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func idsFlag(target **[]int64) func(string) error {
+	return func(raw string) error {
+		ids, err := parseIDs(raw)
+		if err != nil {
+			return err
+		}
+		*target = &ids
+		return nil
+	}
+}
+
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"service/internal/configs"
+	"service/internal/repository"
+	"service/internal/service"
+	"service/pkg/blob"
+	"service/pkg/db"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `Usage: newsctl [flags] <command> [command flags]
+
+Commands:
+  list     [--limit N] [--offset N] [--tags a,b]       список новостей, с --tags - только со всеми этими тегами
+  get      <id>                                        одна новость
+  create   --title T --content C [--content-format plain|markdown|html] [--categories 1,2] [--tags a,b]
+                                                       создать новость
+  edit     <id> [--title T] [--content C] [--content-format F] [--categories 1,2 | --add-categories 1 --remove-categories 2]
+           [--tags a,b]
+  delete   <id>                                        удалить новость
+  import   [--file F] [--format jsonl|csv] [--dry-run] загрузить новости (по умолчанию stdin), обновляя по external_id
+  export   [--file F] [--format jsonl|csv]             выгрузить все новости (по умолчанию stdout)
+  tags     [--prefix P] [--limit N]                    теги по началу, сначала самые используемые
+
+У команд вывода есть --output table|json.
+Флаги подключения те же, что у сервиса: --config, --db-host, --db-dsn и т.д.
+Для delete каталог вложений --media-dir должен существовать: команда удаляет и файлы вложений новости.`
+
+func main() {
+	// Логи в stderr и только предупреждения, чтобы не смешивать их с выводом команд
+	log := logrus.New()
+	log.SetOutput(os.Stderr)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.SetLevel(logrus.WarnLevel)
+
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cnf, opts, err := configs.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cnf.Storage != configs.StorageDatabase {
+		log.Fatalf("newsctl works only with %s storage, got %q", configs.StorageDatabase, cnf.Storage)
+	}
+
+	if len(opts.Args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command, ok := commands[opts.Args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s\n", opts.Args[0], usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, reform, err := db.InitReformDB(ctx, cnf.Database, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer database.Close()
+
+	// Каталог вложений открывается только для команд, которые трогают файлы: остальным хватает базы,
+	// и их можно запускать с машины без каталога сервиса. Каталог не создаётся: новый пустой каталог
+	// значит, что файлы сервиса лежат в другом месте, и удалённая новость оставила бы их навсегда.
+	var media blob.BlobStore = noMediaStore{}
+	if mediaCommands[opts.Args[0]] {
+		if media, err = blob.OpenLocalStore(cnf.Media.Dir); err != nil {
+			database.Close()
+			log.Fatal(err)
+		}
+	}
+
+	// Реплики не используются: после записи команда должна сразу видеть свои изменения
+	newsService := service.NewNewsService(repository.NewNewsRepository(reform, nil, log), media, log)
+
+	if err = command(ctx, newsService, opts.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		database.Close()
+		os.Exit(1)
+	}
+}
+
+// noMediaStore - хранилище вложений для команд без доступа к файлам
+type noMediaStore struct{}
+
+var errNoMediaStore = errors.New("media dir is not opened for this command")
+
+func (noMediaStore) Put(context.Context, string, io.Reader) (int64, error) {
+	return 0, errNoMediaStore
+}
+
+func (noMediaStore) Open(context.Context, string) (io.ReadSeekCloser, error) {
+	return nil, errNoMediaStore
+}
+
+func (noMediaStore) Delete(context.Context, string) error {
+	return errNoMediaStore
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"service/internal/models"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+
+	maxTitleWidth = 60
+)
+
+func outputFlag(fs *flag.FlagSet) *string {
+	output := outputTable
+	fs.Func("output", "output format: table or json", func(v string) error {
+		if v != outputTable && v != outputJSON {
+			return fmt.Errorf("unknown output format %q", v)
+		}
+		output = v
+		return nil
+	})
+	return &output
+}
+
+// JSON вывод повторяет ответы HTTP API
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func printNewsList(w io.Writer, output string, newsList []models.NewsWithCategories) error {
+	if output == outputJSON {
+		if newsList == nil {
+			newsList = []models.NewsWithCategories{}
+		}
+		return printJSON(w, map[string]interface{}{"Success": true, "News": newsList})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tCATEGORIES\tVERSION\tUPDATED AT")
+	for _, news := range newsList {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\n",
+			news.ID, truncate(news.Title, maxTitleWidth), joinIDs(news.Categories), news.Version, formatTime(news.UpdatedAt))
+	}
+	return tw.Flush()
+}
+
+func printNews(w io.Writer, output string, news models.NewsWithCategories) error {
+	if output == outputJSON {
+		return printJSON(w, map[string]interface{}{"Success": true, "News": news})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%d\n", news.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", news.Title)
+	fmt.Fprintf(tw, "Slug:\t%s\n", news.Slug)
+	fmt.Fprintf(tw, "Format:\t%s\n", news.ContentFormat)
+	fmt.Fprintf(tw, "Categories:\t%s\n", joinIDs(news.Categories))
+	fmt.Fprintf(tw, "Tags:\t%s\n", strings.Join(news.Tags, ", "))
+	fmt.Fprintf(tw, "Media:\t%s\n", joinIDs(mediaIDs(news.Media)))
+	fmt.Fprintf(tw, "Version:\t%d\n", news.Version)
+	fmt.Fprintf(tw, "Updated at:\t%s\n", formatTime(news.UpdatedAt))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%s\n", news.Content)
+	return err
+}
+
+func printTags(w io.Writer, output string, tags []models.TagCount) error {
+	if output == outputJSON {
+		return printJSON(w, map[string]interface{}{"Success": true, "Tags": tags})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tNEWS")
+	for _, tag := range tags {
+		fmt.Fprintf(tw, "%s\t%d\n", tag.Name, tag.Count)
+	}
+	return tw.Flush()
+}
+
+func printResult(w io.Writer, output string, result interface{}, message string) error {
+	if output == outputJSON {
+		return printJSON(w, result)
+	}
+	_, err := fmt.Fprintln(w, message)
+	return err
+}
+
+func printImportReport(w io.Writer, output string, report *models.NewsImportReport) error {
+	if output == outputJSON {
+		return printJSON(w, report)
+	}
+
+	if len(report.Errors) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "LINE\tEXTERNAL ID\tERROR")
+		for _, result := range report.Errors {
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", result.Line, result.ExternalID, result.Error)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	prefix := ""
+	if report.DryRun {
+		prefix = "dry run: "
+	}
+	_, err := fmt.Fprintf(w, "%stotal %d, created %d, updated %d, failed %d\n",
+		prefix, report.Total, report.Created, report.Updated, report.Failed)
+	return err
+}
+
+func joinIDs(ids []int64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(parts, ",")
+}
+
+func mediaIDs(media []models.Media) []int64 {
+	ids := make([]int64, 0, len(media))
+	for _, m := range media {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
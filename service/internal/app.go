@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"service/internal/configs"
+	"service/internal/handlers"
+	handler "service/internal/handlers/news"
+	"service/internal/health"
+	"service/internal/repository"
+	"service/internal/service"
+	"service/pkg/ratelimit"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2/middleware/recover"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// AppDeps - зависимости HTTP приложения. Хранилище приходит снаружи,
+// поэтому приложение собирается и без базы, например с репозиториями в памяти в тестах.
+type AppDeps struct {
+	Config      configs.Config
+	NewsService service.INewsService
+	Idempotency repository.IIdempotencyRepository
+	Health      *health.Checker
+	Log         *logrus.Logger
+}
+
+// NewApp собирает fiber приложение со всеми middleware и роутами
+func NewApp(deps AppDeps) *fiber.App {
+	cnf, log := deps.Config, deps.Log
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handlers.ErrorHandler(log),
+		ReadTimeout:  time.Duration(cnf.Service.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(cnf.Service.WriteTimeout) * time.Second,
+		BodyLimit:    cnf.Service.BodyLimit * 1024 * 1024,
+	})
+
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace: true,
+		StackTraceHandler: func(c *fiber.Ctx, e interface{}) {
+			log.WithContext(c.UserContext()).WithFields(logrus.Fields{
+				"panic":  e,
+				"method": c.Method(),
+				"path":   c.Path(),
+			}).Error("Panic recovered")
+		},
+	}))
+
+	app.Use(handlers.RequestID())
+
+	app.Use(handlers.Tracing())
+
+	app.Use(handlers.AccessLog(log))
+
+	app.Use(handlers.Metrics())
+
+	newsHandler := handler.NewNewsHandler(deps.NewsService, cnf.Locale.Default, int64(cnf.Media.MaxSize)*1024*1024, log)
+	handlers.SetupRoutes(app, newsHandler, deps.Health, routeMiddlewares(cnf, deps.Idempotency, log))
+
+	return app
+}
+
+func routeMiddlewares(cnf configs.Config, idempotencyRepo repository.IIdempotencyRepository, log *logrus.Logger) handlers.RouteMiddlewares {
+	var mw handlers.RouteMiddlewares
+	clientID := handlers.ClientID(cnf.Clients.APIKeys)
+
+	if cnf.Storage == configs.StorageDatabase && len(cnf.Database.ReplicaDSNs) > 0 && cnf.Database.PrimaryAfterWrite > 0 {
+		readPrimary := handlers.ReadPrimaryAfterWrite(time.Duration(cnf.Database.PrimaryAfterWrite) * time.Second)
+		mw.Read = append(mw.Read, readPrimary)
+		mw.Write = append(mw.Write, readPrimary)
+	}
+
+	if cnf.RateLimit.Enabled {
+		store := ratelimit.NewMemoryStore()
+		mw.Read = append(mw.Read, handlers.RateLimit(store, clientID, "read", ratelimit.Limit{
+			Rate:  cnf.RateLimit.ReadRate,
+			Burst: cnf.RateLimit.ReadBurst,
+		}, log))
+		mw.Write = append(mw.Write, handlers.RateLimit(store, clientID, "write", ratelimit.Limit{
+			Rate:  cnf.RateLimit.WriteRate,
+			Burst: cnf.RateLimit.WriteBurst,
+		}, log))
+	}
+
+	mw.Upload = slices.Clone(mw.Write)
+	mw.Write = append(mw.Write, handlers.Idempotency(
+		idempotencyRepo,
+		clientID,
+		time.Duration(cnf.Idempotency.KeyTTL)*time.Hour,
+		log,
+	))
+
+	if cnf.Admin.Token != "" {
+		mw.Admin = append(mw.Admin, handlers.AdminAuth(cnf.Admin.Token))
+	} else {
+		log.Info("ADMIN_TOKEN is not set, admin routes are disabled")
+	}
+
+	if cnf.Metrics.Token != "" {
+		mw.Metrics = append(mw.Metrics, handlers.MetricsAuth(cnf.Metrics.Token))
+	}
+
+	return mw
+}
@@ -0,0 +1,1036 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"service/internal/configs"
+	"service/internal/handlers"
+	"service/internal/health"
+	"service/internal/models"
+	"service/internal/newsio"
+	"service/internal/repository"
+	"service/internal/service"
+	"service/pkg/blob"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "secret"
+
+// testApp - приложение на репозиториях в памяти с тремя новостями:
+// 1 "first" с категориями 1 и 2, 2 "second" и 3 "third" без категорий
+type testApp struct {
+	app      *fiber.App
+	repo     repository.INewsRepository
+	checker  *health.Checker
+	mediaDir string
+}
+
+func newTestApp(t *testing.T, cnf configs.Config) testApp {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	repo := repository.NewMemoryNewsRepository(log)
+	for _, form := range []models.NewsCreateForm{
+		{Title: "first", Content: "first content", Categories: &[]int64{1, 2}},
+		{Title: "second", Content: "second content"},
+		{Title: "third", Content: "third content"},
+	} {
+		_, err := repo.CreateNews(context.Background(), form)
+		require.NoError(t, err)
+	}
+
+	mediaDir := t.TempDir()
+	media, err := blob.NewLocalStore(mediaDir)
+	require.NoError(t, err)
+
+	checker := health.NewChecker(time.Second)
+	app := NewApp(AppDeps{
+		Config:      cnf,
+		NewsService: service.NewNewsService(repo, media, log),
+		Idempotency: repository.NewMemoryIdempotencyRepository(log),
+		Health:      checker,
+		Log:         log,
+	})
+
+	return testApp{app: app, repo: repo, checker: checker, mediaDir: mediaDir}
+}
+
+func testConfig() configs.Config {
+	cnf := configs.Default()
+	cnf.Storage = configs.StorageMemory
+	cnf.RateLimit.Enabled = false
+	cnf.Admin.Token = testAdminToken
+	return cnf
+}
+
+func (a testApp) do(t *testing.T, method, path, body string, headers map[string]string) (*http.Response, []byte) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := a.app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, respBody
+}
+
+// assertErrorResponse проверяет единый формат ошибки ErrorHandler: {"Success":false,"Error":"..."}
+func assertErrorResponse(t *testing.T, body []byte, message string) {
+	t.Helper()
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &fields), "body: %s", body)
+	assert.Len(t, fields, 2, "body: %s", body)
+
+	var resp handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.False(t, resp.Success)
+	assert.Equal(t, message, resp.Error)
+}
+
+func decode[T any](t *testing.T, body []byte) T {
+	t.Helper()
+
+	var v T
+	require.NoError(t, json.Unmarshal(body, &v), "body: %s", body)
+	return v
+}
+
+type listResponse struct {
+	Success bool
+	News    []models.NewsWithCategories
+}
+
+type newsResponse struct {
+	Success bool
+	News    models.NewsWithCategories
+}
+
+type createResponse struct {
+	Success bool
+	Id      int64
+}
+
+type mediaResponse struct {
+	Success bool
+	Media   models.Media
+}
+
+type tagsResponse struct {
+	Success bool
+	Tags    []models.TagCount
+}
+
+type batchResponse struct {
+	Success bool
+	Results []models.NewsBatchResult
+}
+
+func TestAppRoutes(t *testing.T) {
+	admin := map[string]string{fiber.HeaderAuthorization: "Bearer " + testAdminToken}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		headers    map[string]string
+		wantStatus int
+		wantError  string
+		check      func(t *testing.T, a testApp, resp *http.Response, body []byte)
+	}{
+		{
+			name: "liveness", method: http.MethodGet, path: "/healthz",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, _ *http.Response, body []byte) {
+				assert.Equal(t, health.StatusOK, decode[health.Report](t, body).Status)
+			},
+		},
+		{
+			name: "readiness", method: http.MethodGet, path: "/readyz",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, _ *http.Response, body []byte) {
+				assert.Equal(t, health.StatusOK, decode[health.Report](t, body).Status)
+			},
+		},
+		{
+			name: "metrics", method: http.MethodGet, path: "/metrics",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, _ *http.Response, body []byte) {
+				assert.Contains(t, string(body), "http_requests_total")
+			},
+		},
+		{
+			name: "list", method: http.MethodGet, path: "/list",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, resp *http.Response, body []byte) {
+				list := decode[listResponse](t, body)
+				assert.True(t, list.Success)
+				require.Len(t, list.News, 3)
+				assert.Equal(t, []string{"third", "second", "first"}, newsTitles(list.News))
+				assert.Equal(t, []int64{1, 2}, list.News[2].Categories)
+				assert.Equal(t, []int64{}, list.News[0].Categories)
+				assert.NotEmpty(t, resp.Header.Get(fiber.HeaderETag))
+				assert.NotEmpty(t, resp.Header.Get(fiber.HeaderLastModified))
+			},
+		},
+		{
+			name: "list page", method: http.MethodGet, path: "/list?limit=1&offset=1",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, _ *http.Response, body []byte) {
+				assert.Equal(t, []string{"second"}, newsTitles(decode[listResponse](t, body).News))
+			},
+		},
+		{
+			name: "get", method: http.MethodGet, path: "/news/1",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, resp *http.Response, body []byte) {
+				n := decode[newsResponse](t, body)
+				assert.True(t, n.Success)
+				assert.Equal(t, int64(1), n.News.ID)
+				assert.Equal(t, "first", n.News.Title)
+				assert.Equal(t, "first content", n.News.Content)
+				assert.Equal(t, int64(1), n.News.Version)
+				assert.Equal(t, []int64{1, 2}, n.News.Categories)
+				assert.NotEmpty(t, resp.Header.Get(fiber.HeaderETag))
+			},
+		},
+		{
+			name: "get invalid id", method: http.MethodGet, path: "/news/abc",
+			wantStatus: http.StatusBadRequest, wantError: "Invalid ID format",
+		},
+		{
+			name: "get not found", method: http.MethodGet, path: "/news/42",
+			wantStatus: http.StatusNotFound, wantError: "News not found",
+		},
+		{
+			name: "create", method: http.MethodPost, path: "/create",
+			body:       `{"title":"  new  ","content":"new content","categories":[3]}`,
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, a testApp, _ *http.Response, body []byte) {
+				created := decode[createResponse](t, body)
+				assert.True(t, created.Success)
+				assert.Equal(t, int64(4), created.Id)
+
+				n, err := a.repo.GetNewsByID(context.Background(), created.Id)
+				require.NoError(t, err)
+				assert.Equal(t, "new", n.Title, "title is trimmed")
+				assert.Equal(t, []int64{3}, n.Categories)
+			},
+		},
+		{
+			name: "create invalid body", method: http.MethodPost, path: "/create",
+			body:       `{"title":`,
+			wantStatus: http.StatusBadRequest, wantError: "Invalid request body",
+		},
+		{
+			name: "edit", method: http.MethodPost, path: "/edit/1",
+			body:       `{"title":"edited","add_categories":[5],"remove_categories":[1]}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, a testApp, _ *http.Response, body []byte) {
+				assert.Equal(t, `{"Success":true}`, string(body))
+
+				n, err := a.repo.GetNewsByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, "edited", n.Title)
+				assert.Equal(t, "first content", n.Content)
+				assert.Equal(t, []int64{2, 5}, n.Categories)
+				assert.Equal(t, int64(2), n.Version)
+			},
+		},
+		{
+			name: "edit invalid id", method: http.MethodPost, path: "/edit/abc",
+			body:       `{"title":"edited"}`,
+			wantStatus: http.StatusBadRequest, wantError: "Invalid ID format",
+		},
+		{
+			name: "edit invalid body", method: http.MethodPost, path: "/edit/1",
+			body:       `[]`,
+			wantStatus: http.StatusBadRequest, wantError: "Invalid request body",
+		},
+		{
+			name: "edit not found", method: http.MethodPost, path: "/edit/42",
+			body:       `{"title":"edited"}`,
+			wantStatus: http.StatusNotFound, wantError: "News not found",
+		},
+		{
+			name: "batch", method: http.MethodPost, path: "/news/batch",
+			body:       `{"operations":[{"op":"create","data":{"title":"a","content":"b"}},{"op":"edit","id":42,"data":{"title":"c"}},{"op":"delete","id":1,"data":{}}]}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, _ *http.Response, body []byte) {
+				batch := decode[batchResponse](t, body)
+				assert.False(t, batch.Success)
+				require.Len(t, batch.Results, 3)
+				assert.Equal(t, []int{201, 404, 400}, []int{batch.Results[0].Status, batch.Results[1].Status, batch.Results[2].Status})
+				assert.Equal(t, `op must be one of "create", "edit"`, batch.Results[2].Error)
+			},
+		},
+		{
+			name: "batch atomic rolled back", method: http.MethodPost, path: "/news/batch?atomic=true",
+			body:       `{"operations":[{"op":"create","data":{"title":"a","content":"b"}},{"op":"edit","id":42,"data":{"title":"c"}}]}`,
+			wantStatus: http.StatusConflict,
+			check: func(t *testing.T, a testApp, _ *http.Response, body []byte) {
+				batch := decode[batchResponse](t, body)
+				require.Len(t, batch.Results, 2)
+				assert.Equal(t, "rolled back", batch.Results[0].Error)
+				assert.Equal(t, "News not found", batch.Results[1].Error)
+
+				newsList, err := a.repo.GetNews(context.Background(), 10, 0, models.NewsFilter{})
+				require.NoError(t, err)
+				assert.Len(t, newsList, 3)
+			},
+		},
+		{
+			name: "batch atomic invalid", method: http.MethodPost, path: "/news/batch?atomic=true",
+			body:       `{"operations":[{"op":"create","data":{"title":"a","content":"b"}},{"op":"create","data":{"title":"","content":"b"}}]}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, _ testApp, _ *http.Response, body []byte) {
+				batch := decode[batchResponse](t, body)
+				require.Len(t, batch.Results, 1)
+				assert.Equal(t, 1, batch.Results[0].Index)
+				assert.Equal(t, models.ErrTitleLength.Error(), batch.Results[0].Error)
+			},
+		},
+		{
+			name: "batch empty", method: http.MethodPost, path: "/news/batch",
+			body:       `{"operations":[]}`,
+			wantStatus: http.StatusBadRequest, wantError: "operations cannot be empty",
+		},
+		{
+			name: "admin export", method: http.MethodGet, path: "/admin/export",
+			headers:    admin,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, resp *http.Response, body []byte) {
+				assert.Equal(t, newsio.ContentType(newsio.FormatJSONL), resp.Header.Get(fiber.HeaderContentType))
+				lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+				require.Len(t, lines, 3)
+				assert.Equal(t, "first", decode[models.NewsWithCategories](t, []byte(lines[0])).Title)
+			},
+		},
+		{
+			name: "admin export csv", method: http.MethodGet, path: "/admin/export?format=csv",
+			headers:    admin,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, _ *http.Response, body []byte) {
+				lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+				require.Len(t, lines, 4)
+				assert.Equal(t, "id,external_id,slug,title,content,content_format,categories,tags,version,updated_at", lines[0])
+			},
+		},
+		{
+			name: "admin export unknown format", method: http.MethodGet, path: "/admin/export?format=xml",
+			headers:    admin,
+			wantStatus: http.StatusBadRequest, wantError: newsio.ErrUnknownFormat.Error(),
+		},
+		{
+			name: "admin export without token", method: http.MethodGet, path: "/admin/export",
+			wantStatus: http.StatusUnauthorized, wantError: "Invalid admin token",
+		},
+		{
+			name: "admin import", method: http.MethodPost, path: "/admin/import",
+			body:       "{\"external_id\":\"ext-1\",\"title\":\"imported\",\"content\":\"content\"}\n{\"title\":\"\"}\n",
+			headers:    admin,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ testApp, _ *http.Response, body []byte) {
+				report := decode[models.NewsImportReport](t, body)
+				assert.Equal(t, 2, report.Total)
+				assert.Equal(t, 1, report.Created)
+				assert.Equal(t, 1, report.Failed)
+				require.Len(t, report.Errors, 1)
+				assert.Equal(t, 2, report.Errors[0].Line)
+				assert.Equal(t, models.ErrTitleLength.Error(), report.Errors[0].Error)
+			},
+		},
+		{
+			name: "admin import wrong token", method: http.MethodPost, path: "/admin/import",
+			body:       `{"title":"imported","content":"content"}`,
+			headers:    map[string]string{fiber.HeaderAuthorization: "Bearer wrong"},
+			wantStatus: http.StatusUnauthorized, wantError: "Invalid admin token",
+		},
+		{
+			name: "unknown route", method: http.MethodGet, path: "/unknown",
+			wantStatus: http.StatusNotFound, wantError: "Cannot GET /unknown",
+		},
+		{
+			name: "wrong method", method: http.MethodGet, path: "/create",
+			wantStatus: http.StatusMethodNotAllowed, wantError: "Method Not Allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t, testConfig())
+
+			resp, body := a.do(t, tt.method, tt.path, tt.body, tt.headers)
+			require.Equal(t, tt.wantStatus, resp.StatusCode, "body: %s", body)
+			assert.NotEmpty(t, resp.Header.Get(fiber.HeaderXRequestID))
+
+			if tt.wantError != "" {
+				assertErrorResponse(t, body, tt.wantError)
+			}
+			if tt.check != nil {
+				tt.check(t, a, resp, body)
+			}
+		})
+	}
+}
+
+func TestAppValidationMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		want error
+	}{
+		{name: "create empty title", path: "/create", body: `{"title":"","content":"content"}`, want: models.ErrTitleLength},
+		{name: "create blank title", path: "/create", body: `{"title":"   ","content":"content"}`, want: models.ErrTitleLength},
+		{name: "create long title", path: "/create", body: fmt.Sprintf(`{"title":%q,"content":"content"}`, strings.Repeat("я", 256)), want: models.ErrTitleLength},
+		{name: "create empty content", path: "/create", body: `{"title":"title","content":" "}`, want: models.ErrContentLength},
+		{name: "create unknown content_format", path: "/create", body: `{"title":"title","content":"content","content_format":"rst"}`, want: models.ErrContentFormat},
+		{name: "edit empty body", path: "/edit/1", body: `{}`, want: models.ErrBodyEmpty},
+		{name: "edit empty title", path: "/edit/1", body: `{"title":" "}`, want: models.ErrTitleLength},
+		{name: "edit long title", path: "/edit/1", body: fmt.Sprintf(`{"title":%q}`, strings.Repeat("a", 256)), want: models.ErrTitleLength},
+		{name: "edit empty content", path: "/edit/1", body: `{"content":""}`, want: models.ErrContentLength},
+		{name: "edit empty content_format", path: "/edit/1", body: `{"content_format":""}`, want: models.ErrContentFormat},
+		{name: "edit empty categories", path: "/edit/1", body: `{"categories":[]}`, want: models.ErrCategoriesLength},
+		{name: "edit empty add_categories", path: "/edit/1", body: `{"add_categories":[]}`, want: models.ErrCategoriesLength},
+		{name: "edit empty remove_categories", path: "/edit/1", body: `{"remove_categories":[]}`, want: models.ErrCategoriesLength},
+		{name: "edit mixed categories", path: "/edit/1", body: `{"categories":[1],"add_categories":[2]}`, want: models.ErrCategoriesMixed},
+		{name: "edit clashing categories", path: "/edit/1", body: `{"add_categories":[1,2],"remove_categories":[2]}`, want: models.ErrCategoriesClash},
+		{name: "create long tag", path: "/create", body: fmt.Sprintf(`{"title":"title","content":"content","tags":[%q]}`, strings.Repeat("я", 65)), want: models.ErrTagLength},
+		{name: "create blank tag", path: "/create", body: `{"title":"title","content":"content","tags":["  "]}`, want: models.ErrTagLength},
+		{name: "create tag with comma", path: "/create", body: `{"title":"title","content":"content","tags":["a,b"]}`, want: models.ErrTagComma},
+		{name: "create too many tags", path: "/create", body: fmt.Sprintf(`{"title":"title","content":"content","tags":%s}`, distinctTags(models.MaxTags+1)), want: models.ErrTagsLength},
+		{name: "edit blank tag", path: "/edit/1", body: `{"tags":[""]}`, want: models.ErrTagLength},
+		{name: "translation empty title", path: "/news/1/translations/en", body: `{"title":" ","content":"content"}`, want: models.ErrTitleLength},
+		{name: "translation empty content", path: "/news/1/translations/en", body: `{"title":"title"}`, want: models.ErrContentLength},
+		{name: "translation unknown content_format", path: "/news/1/translations/en", body: `{"title":"title","content":"content","content_format":"rst"}`, want: models.ErrContentFormat},
+		{name: "translation invalid locale", path: "/news/1/translations/not_a_locale!", body: `{"title":"title","content":"content"}`, want: models.ErrLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t, testConfig())
+
+			resp, body := a.do(t, http.MethodPost, tt.path, tt.body, nil)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode, "body: %s", body)
+			assertErrorResponse(t, body, tt.want.Error())
+		})
+	}
+
+	t.Run("title of 255 runes is valid", func(t *testing.T) {
+		a := newTestApp(t, testConfig())
+
+		body := fmt.Sprintf(`{"title":%q,"content":"content"}`, strings.Repeat("я", 255))
+		resp, respBody := a.do(t, http.MethodPost, "/create", body, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "body: %s", respBody)
+	})
+}
+
+func TestAppPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantError  string
+		wantTitles []string
+	}{
+		{name: "defaults", query: "", wantStatus: http.StatusOK, wantTitles: []string{"third", "second", "first"}},
+		{name: "min limit", query: "?limit=1", wantStatus: http.StatusOK, wantTitles: []string{"third"}},
+		{name: "max limit", query: "?limit=100", wantStatus: http.StatusOK, wantTitles: []string{"third", "second", "first"}},
+		{name: "last page", query: "?limit=2&offset=2", wantStatus: http.StatusOK, wantTitles: []string{"first"}},
+		{name: "offset past end", query: "?offset=3", wantStatus: http.StatusOK, wantTitles: nil},
+		{name: "zero limit", query: "?limit=0", wantStatus: http.StatusBadRequest, wantError: "limit must be greater or equal 1"},
+		{name: "negative limit", query: "?limit=-1", wantStatus: http.StatusBadRequest, wantError: "limit must be greater or equal 1"},
+		{name: "limit above max", query: "?limit=101", wantStatus: http.StatusBadRequest, wantError: "limit must be less or equal to 100"},
+		{name: "negative offset", query: "?offset=-1", wantStatus: http.StatusBadRequest, wantError: "offset cannot be negative"},
+		{name: "limit not a number", query: "?limit=ten", wantStatus: http.StatusBadRequest, wantError: "limit must be a valid number"},
+		{name: "offset not a number", query: "?offset=1.5", wantStatus: http.StatusBadRequest, wantError: "offset must be a valid number"},
+	}
+
+	a := newTestApp(t, testConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := a.do(t, http.MethodGet, "/list"+tt.query, "", nil)
+			require.Equal(t, tt.wantStatus, resp.StatusCode, "body: %s", body)
+
+			if tt.wantError != "" {
+				assertErrorResponse(t, body, tt.wantError)
+				return
+			}
+			assert.Equal(t, tt.wantTitles, newsTitles(decode[listResponse](t, body).News))
+		})
+	}
+}
+
+func TestAppConditionalGet(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	resp, _ := a.do(t, http.MethodGet, "/news/1", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get(fiber.HeaderETag)
+
+	resp, body := a.do(t, http.MethodGet, "/news/1", "", map[string]string{fiber.HeaderIfNoneMatch: etag})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	assert.Empty(t, body)
+
+	resp, _ = a.do(t, http.MethodPost, "/edit/1", `{"title":"edited"}`, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, _ = a.do(t, http.MethodGet, "/news/1", "", map[string]string{fiber.HeaderIfNoneMatch: etag})
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "edit changes ETag")
+
+	// Список сверяется с If-Modified-Since по самому свежему updated_at на странице
+	later := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	earlier := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	resp, _ = a.do(t, http.MethodGet, "/list", "", map[string]string{fiber.HeaderIfModifiedSince: later})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	resp, _ = a.do(t, http.MethodGet, "/list", "", map[string]string{fiber.HeaderIfModifiedSince: earlier})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Удаление не сдвигает Last-Modified, поэтому устаревший If-None-Match побеждает If-Modified-Since
+	resp, _ = a.do(t, http.MethodGet, "/list", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	listETag := resp.Header.Get(fiber.HeaderETag)
+	_, err := a.repo.DeleteNews(context.Background(), 2)
+	require.NoError(t, err)
+	resp, _ = a.do(t, http.MethodGet, "/list", "", map[string]string{fiber.HeaderIfNoneMatch: listETag, fiber.HeaderIfModifiedSince: later})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestAppRender(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	resp, body := a.do(t, http.MethodPost, "/create",
+		`{"title":"md","content":"# Hi <script>alert(1)</script>","content_format":"Markdown"}`, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "body: %s", body)
+	path := fmt.Sprintf("/news/%d", decode[createResponse](t, body).Id)
+
+	for _, query := range []string{"", "?render=raw"} {
+		resp, body = a.do(t, http.MethodGet, path+query, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+		n := decode[newsResponse](t, body).News
+		assert.Equal(t, "# Hi <script>alert(1)</script>", n.Content, "query %q", query)
+		assert.Equal(t, "markdown", n.ContentFormat, "query %q", query)
+	}
+
+	resp, body = a.do(t, http.MethodGet, path+"?render=html", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+	n := decode[newsResponse](t, body).News
+	assert.Equal(t, "<h1>Hi </h1>\n", n.Content, "script is stripped")
+	assert.Equal(t, "html", n.ContentFormat)
+	assert.NotContains(t, string(body), "ContentHTML")
+
+	resp, body = a.do(t, http.MethodGet, "/list?render=html", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+	newsList := decode[listResponse](t, body).News
+	require.Len(t, newsList, 4)
+	assert.Equal(t, "<p>third content</p>", newsList[1].Content)
+
+	resp, body = a.do(t, http.MethodGet, path+"?render=pdf", "", nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assertErrorResponse(t, body, "render must be html or raw")
+}
+
+func TestAppNewsBySlug(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	resp, body := a.do(t, http.MethodGet, "/news/by-slug/first", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+	n := decode[newsResponse](t, body).News
+	assert.Equal(t, int64(1), n.ID)
+	assert.Equal(t, "first", n.Slug)
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderETag))
+
+	resp, body = a.do(t, http.MethodPost, "/edit/1", `{"title":"Первая новость"}`, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+
+	resp, _ = a.do(t, http.MethodGet, "/news/by-slug/first?render=html", "", nil)
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/news/by-slug/pervaya-novost?render=html", resp.Header.Get(fiber.HeaderLocation))
+
+	resp, body = a.do(t, http.MethodGet, "/news/by-slug/pervaya-novost?render=html", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+	assert.Equal(t, "<p>first content</p>", decode[newsResponse](t, body).News.Content)
+
+	resp, body = a.do(t, http.MethodGet, "/news/by-slug/unknown", "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assertErrorResponse(t, body, "News not found")
+}
+
+func TestAppTranslations(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	resp, body := a.do(t, http.MethodPost, "/news/1/translations/EN", `{"title":"First","content":"**first**","content_format":"markdown"}`, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "body: %s", body)
+	resp, body = a.do(t, http.MethodPost, "/news/1/translations/de-AT", `{"title":"Erste","content":"erste"}`, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "body: %s", body)
+
+	resp, body = a.do(t, http.MethodPost, "/news/1/translations/en", `{"title":"First","content":"first"}`, nil)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	assertErrorResponse(t, body, "Translation already exists")
+
+	resp, body = a.do(t, http.MethodPost, "/news/1/translations/ru", `{"title":"Первая","content":"первая"}`, nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assertErrorResponse(t, body, "locale must differ from the default locale, edit the news itself instead")
+
+	resp, _ = a.do(t, http.MethodPost, "/news/999/translations/en", `{"title":"First","content":"first"}`, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	tests := []struct {
+		name     string
+		path     string
+		headers  map[string]string
+		title    string
+		locale   string
+		contents string
+	}{
+		{name: "default without preferences", path: "/news/1", title: "first", locale: "ru"},
+		{name: "accept-language", path: "/news/1", headers: map[string]string{"Accept-Language": "en-US,en;q=0.9"}, title: "First", locale: "en"},
+		{name: "lang over accept-language", path: "/news/1?lang=de-AT", headers: map[string]string{"Accept-Language": "en"}, title: "Erste", locale: "de-AT"},
+		{name: "fallback to next preference", path: "/news/1", headers: map[string]string{"Accept-Language": "fr, de-AT;q=0.8, en;q=0.5"}, title: "Erste", locale: "de-AT"},
+		{name: "default locale stops the chain", path: "/news/1", headers: map[string]string{"Accept-Language": "fr, ru;q=0.9, en;q=0.8"}, title: "first", locale: "ru"},
+		{name: "no translation", path: "/news/2", headers: map[string]string{"Accept-Language": "en"}, title: "second", locale: "ru"},
+		{name: "malformed accept-language", path: "/news/1", headers: map[string]string{"Accept-Language": ";;;"}, title: "first", locale: "ru"},
+		{name: "rendered translation", path: "/news/1?lang=en&render=html", title: "First", locale: "en", contents: "<p><strong>first</strong></p>\n"},
+		{name: "by slug", path: "/news/by-slug/first?lang=en", title: "First", locale: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := a.do(t, http.MethodGet, tt.path, "", tt.headers)
+			require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+			n := decode[newsResponse](t, body).News
+			assert.Equal(t, tt.title, n.Title)
+			assert.Equal(t, tt.locale, n.Locale)
+			assert.Equal(t, tt.locale, resp.Header.Get(fiber.HeaderContentLanguage))
+			assert.Equal(t, fiber.HeaderAcceptLanguage, resp.Header.Get(fiber.HeaderVary))
+			if tt.contents != "" {
+				assert.Equal(t, tt.contents, n.Content)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		resp, body := a.do(t, http.MethodGet, "/list?lang=en", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+		newsList := decode[listResponse](t, body).News
+		assert.Equal(t, []string{"third", "second", "First"}, newsTitles(newsList))
+		assert.Equal(t, []string{"ru", "ru", "en"}, []string{newsList[0].Locale, newsList[1].Locale, newsList[2].Locale})
+	})
+
+	t.Run("invalid lang", func(t *testing.T) {
+		resp, body := a.do(t, http.MethodGet, "/news/1?lang=!!", "", nil)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assertErrorResponse(t, body, "lang must be a valid BCP 47 language tag")
+	})
+
+	t.Run("edit", func(t *testing.T) {
+		before, err := a.repo.GetNewsByID(context.Background(), 1)
+		require.NoError(t, err)
+
+		resp, body := a.do(t, http.MethodPatch, "/news/1/translations/en", `{"title":"First!"}`, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+
+		after, err := a.repo.GetNewsByID(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Greater(t, after.Version, before.Version, "translation edit changes the news version")
+
+		resp, body = a.do(t, http.MethodGet, "/news/1?lang=en", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "First!", decode[newsResponse](t, body).News.Title)
+
+		resp, body = a.do(t, http.MethodPatch, "/news/1/translations/en", `{}`, nil)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assertErrorResponse(t, body, models.ErrBodyEmpty.Error())
+
+		resp, body = a.do(t, http.MethodPatch, "/news/1/translations/fr", `{"title":"Premier"}`, nil)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assertErrorResponse(t, body, "Translation not found")
+	})
+}
+
+func TestAppTags(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	resp, body := a.do(t, http.MethodPost, "/create", `{"title":"elections","content":"content","tags":["  Выборы   2026 ","politics","POLITICS"]}`, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "body: %s", body)
+	id := decode[createResponse](t, body).Id
+
+	resp, body = a.do(t, http.MethodGet, fmt.Sprintf("/news/%d", id), "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"politics", "выборы-2026"}, decode[newsResponse](t, body).News.Tags, "tags are normalized")
+
+	resp, body = a.do(t, http.MethodPost, "/edit/1", `{"tags":["Politics","economy"]}`, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+
+	resp, body = a.do(t, http.MethodGet, "/list?tag=POLITICS", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+	assert.Equal(t, []string{"elections", "first"}, newsTitles(decode[listResponse](t, body).News))
+
+	resp, body = a.do(t, http.MethodGet, "/list?tag=politics&tag=economy", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"first"}, newsTitles(decode[listResponse](t, body).News))
+
+	resp, body = a.do(t, http.MethodGet, "/list?tag=a,b", "", nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assertErrorResponse(t, body, models.ErrTagComma.Error())
+
+	resp, body = a.do(t, http.MethodGet, "/tags", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+	assert.Equal(t, []models.TagCount{{Name: "politics", Count: 2}, {Name: "economy", Count: 1}, {Name: "выборы-2026", Count: 1}},
+		decode[tagsResponse](t, body).Tags)
+
+	resp, body = a.do(t, http.MethodGet, "/tags?prefix="+url.QueryEscape("Выборы 20")+"&limit=5", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+	assert.Equal(t, []models.TagCount{{Name: "выборы-2026", Count: 1}}, decode[tagsResponse](t, body).Tags)
+
+	resp, body = a.do(t, http.MethodGet, "/tags?prefix=zzz", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []models.TagCount{}, decode[tagsResponse](t, body).Tags)
+
+	resp, _ = a.do(t, http.MethodGet, "/tags?limit=0", "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, body = a.do(t, http.MethodPost, "/edit/1", `{"tags":[]}`, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "body: %s", body)
+	resp, body = a.do(t, http.MethodGet, "/news/1", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, decode[newsResponse](t, body).News.Tags, "empty list removes all tags")
+}
+
+func TestAppMedia(t *testing.T) {
+	cnf := testConfig()
+	cnf.Media.MaxSize = 1
+	a := newTestApp(t, cnf)
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1, 2, 3, 4}, 100)...)
+	sum := sha256.Sum256(png)
+	checksum := hex.EncodeToString(sum[:])
+
+	// Заявленный клиентом тип не важен, тип определяется по содержимому
+	body, contentType := multipartUpload(t, "photo.txt", png, "  Фото с места событий ")
+	resp, respBody := a.do(t, http.MethodPost, "/news/1/media", body, map[string]string{fiber.HeaderContentType: contentType})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "body: %s", respBody)
+	media := decode[mediaResponse](t, respBody).Media
+	assert.Equal(t, fmt.Sprintf("/media/%d", media.ID), resp.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, int64(1), media.NewsID)
+	assert.Equal(t, "image/png", media.MimeType)
+	assert.Equal(t, int64(len(png)), media.Size)
+	assert.Equal(t, checksum, media.Checksum)
+	assert.Equal(t, "Фото с места событий", media.AltText)
+	assert.NotContains(t, string(respBody), "StorageKey")
+
+	resp, respBody = a.do(t, http.MethodGet, "/news/1", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	news := decode[newsResponse](t, respBody).News
+	require.Len(t, news.Media, 1)
+	assert.Equal(t, media.ID, news.Media[0].ID)
+	assert.Equal(t, int64(2), news.Version, "attaching media bumps the version")
+
+	mediaPath := fmt.Sprintf("/media/%d", media.ID)
+	resp, respBody = a.do(t, http.MethodGet, mediaPath, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, png, respBody)
+	assert.Equal(t, "image/png", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, `"`+checksum+`"`, resp.Header.Get(fiber.HeaderETag))
+	assert.Equal(t, "public, max-age=31536000, immutable", resp.Header.Get(fiber.HeaderCacheControl))
+	assert.Equal(t, "nosniff", resp.Header.Get(fiber.HeaderXContentTypeOptions))
+	assert.Equal(t, "bytes", resp.Header.Get(fiber.HeaderAcceptRanges))
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderLastModified))
+
+	resp, respBody = a.do(t, http.MethodGet, mediaPath, "", map[string]string{fiber.HeaderRange: "bytes=0-7"})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, png[:8], respBody)
+	assert.Equal(t, fmt.Sprintf("bytes 0-7/%d", len(png)), resp.Header.Get(fiber.HeaderContentRange))
+
+	resp, _ = a.do(t, http.MethodGet, mediaPath, "", map[string]string{fiber.HeaderRange: fmt.Sprintf("bytes=%d-", len(png))})
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+
+	resp, respBody = a.do(t, http.MethodGet, mediaPath, "", map[string]string{fiber.HeaderRange: "bytes=-4"})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, png[len(png)-4:], respBody)
+
+	// Несколько диапазонов и устаревший If-Range отдают файл целиком
+	for _, headers := range []map[string]string{
+		{fiber.HeaderRange: "bytes=0-1,4-5"},
+		{fiber.HeaderRange: "bytes=0-7", fiber.HeaderIfRange: `"stale"`},
+	} {
+		resp, respBody = a.do(t, http.MethodGet, mediaPath, "", headers)
+		require.Equal(t, http.StatusOK, resp.StatusCode, "headers: %v", headers)
+		assert.Equal(t, png, respBody)
+	}
+
+	resp, respBody = a.do(t, http.MethodGet, mediaPath, "", map[string]string{fiber.HeaderRange: "bytes=0-7", fiber.HeaderIfRange: `"` + checksum + `"`})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, png[:8], respBody)
+
+	resp, respBody = a.do(t, http.MethodGet, mediaPath, "", map[string]string{fiber.HeaderIfNoneMatch: `"` + checksum + `"`})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	assert.Empty(t, respBody)
+
+	lastModified := resp.Header.Get(fiber.HeaderLastModified)
+	resp, _ = a.do(t, http.MethodGet, mediaPath, "", map[string]string{fiber.HeaderIfModifiedSince: lastModified})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	resp, _ = a.do(t, http.MethodGet, mediaPath, "", map[string]string{fiber.HeaderIfNoneMatch: `"other"`, fiber.HeaderIfModifiedSince: lastModified})
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "If-None-Match takes precedence over If-Modified-Since")
+
+	resp, respBody = a.do(t, http.MethodHead, mediaPath, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, respBody)
+	assert.Equal(t, strconv.Itoa(len(png)), resp.Header.Get(fiber.HeaderContentLength))
+
+	resp, respBody = a.do(t, http.MethodGet, "/media/999", "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assertErrorResponse(t, respBody, "Media not found")
+
+	resp, _ = a.do(t, http.MethodGet, "/media/abc", "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	tests := []struct {
+		name    string
+		path    string
+		file    []byte
+		alt     string
+		status  int
+		message string
+	}{
+		{name: "not an image", path: "/news/1/media", file: []byte("<svg onload=alert(1)></svg>"), status: http.StatusUnsupportedMediaType, message: models.ErrMediaType.Error()},
+		{name: "empty file", path: "/news/1/media", file: []byte{}, status: http.StatusBadRequest, message: "file cannot be empty"},
+		{name: "too large", path: "/news/1/media", file: append(png, make([]byte, 1<<20)...), status: http.StatusRequestEntityTooLarge, message: "file size must be less or equal to 1 MB"},
+		{name: "long alt", path: "/news/1/media", file: png, alt: strings.Repeat("a", models.MaxAltTextLength+1), status: http.StatusBadRequest, message: models.ErrAltTextLength.Error()},
+		{name: "unknown news", path: "/news/999/media", file: png, status: http.StatusNotFound, message: "News not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := multipartUpload(t, "file", tt.file, tt.alt)
+			resp, respBody := a.do(t, http.MethodPost, tt.path, body, map[string]string{fiber.HeaderContentType: contentType})
+			require.Equal(t, tt.status, resp.StatusCode, "body: %s", respBody)
+			assertErrorResponse(t, respBody, tt.message)
+		})
+	}
+
+	resp, respBody = a.do(t, http.MethodPost, "/news/1/media", `{"file":"data"}`, nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assertErrorResponse(t, respBody, "file is required in multipart/form-data body")
+
+	// В хранилище остался только принятый файл: отклонённые загрузки не оставляют файлов
+	assert.Equal(t, 1, countFiles(t, a.mediaDir))
+}
+
+func TestAppIdempotency(t *testing.T) {
+	cnf := testConfig()
+	cnf.Clients.APIKeys = []string{"first-key", "second-key"}
+	a := newTestApp(t, cnf)
+	key := map[string]string{handlers.HeaderIdempotencyKey: "create-1"}
+
+	resp, first := a.do(t, http.MethodPost, "/create", `{"title":"title","content":"content"}`, key)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "body: %s", first)
+
+	resp, replayed := a.do(t, http.MethodPost, "/create", `{"title":"title","content":"content"}`, key)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(handlers.HeaderIdempotentReplayed))
+	assert.Equal(t, first, replayed)
+
+	resp, body := a.do(t, http.MethodPost, "/create", `{"title":"other","content":"content"}`, key)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assertErrorResponse(t, body, "Idempotency-Key was already used with a different request")
+
+	// Строка запроса меняет смысл запроса и входит в его хэш
+	batchKey := map[string]string{handlers.HeaderIdempotencyKey: "batch-1"}
+	batch := `{"operations":[{"op":"create","data":{"title":"a","content":"b"}}]}`
+	resp, _ = a.do(t, http.MethodPost, "/news/batch", batch, batchKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, body = a.do(t, http.MethodPost, "/news/batch?atomic=true", batch, batchKey)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assertErrorResponse(t, body, "Idempotency-Key was already used with a different request")
+
+	// Ключи принадлежат клиенту: тот же ключ другого клиента выполняется заново
+	shared := func(apiKey string) map[string]string {
+		return map[string]string{handlers.HeaderIdempotencyKey: "shared", handlers.HeaderAPIKey: apiKey}
+	}
+	resp, first = a.do(t, http.MethodPost, "/create", `{"title":"title","content":"content"}`, shared("first-key"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, second := a.do(t, http.MethodPost, "/create", `{"title":"title","content":"content"}`, shared("second-key"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(handlers.HeaderIdempotentReplayed))
+	assert.NotEqual(t, first, second)
+
+	newsList, err := a.repo.GetNews(context.Background(), 10, 0, models.NewsFilter{})
+	require.NoError(t, err)
+	assert.Len(t, newsList, 7, "replay does not create news")
+
+	// Загрузка файла не проходит через идемпотентность: тело размером с файл не хэшируется и не хранится
+	upload, contentType := multipartUpload(t, "photo.png", []byte("\x89PNG\r\n\x1a\nimage"), "")
+	uploadKey := map[string]string{handlers.HeaderIdempotencyKey: "upload-1", fiber.HeaderContentType: contentType}
+	for range 2 {
+		resp, body = a.do(t, http.MethodPost, "/news/1/media", upload, uploadKey)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "body: %s", body)
+		assert.Empty(t, resp.Header.Get(handlers.HeaderIdempotentReplayed))
+	}
+}
+
+func TestAppRateLimit(t *testing.T) {
+	cnf := testConfig()
+	cnf.RateLimit.Enabled = true
+	cnf.RateLimit.WriteRate = 0.001
+	cnf.RateLimit.WriteBurst = 1
+	cnf.Clients.APIKeys = []string{"client-key"}
+	a := newTestApp(t, cnf)
+
+	resp, _ := a.do(t, http.MethodPost, "/create", `{"title":"title","content":"content"}`, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, body := a.do(t, http.MethodPost, "/create", `{"title":"title","content":"content"}`, nil)
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.False(t, decode[handlers.ErrorResponse](t, body).Success)
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+
+	resp, _ = a.do(t, http.MethodGet, "/list", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "reads have their own limit")
+
+	// Неизвестный ключ не даёт новой корзины, клиент остаётся в корзине своего IP
+	resp, _ = a.do(t, http.MethodPost, "/create", `{"title":"title","content":"content"}`, map[string]string{handlers.HeaderAPIKey: "random"})
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	resp, _ = a.do(t, http.MethodPost, "/create", `{"title":"title","content":"content"}`, map[string]string{handlers.HeaderAPIKey: "client-key"})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode, "known key has its own bucket")
+}
+
+func TestAppMetricsCountErrors(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	resp, _ := a.do(t, http.MethodGet, "/news/42", "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, body := a.do(t, http.MethodGet, "/metrics", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/news/:id",status="404"}`,
+		"status of an error is taken from the error, not from the unfinished response")
+}
+
+func TestAppMetricsToken(t *testing.T) {
+	cnf := testConfig()
+	cnf.Metrics.Token = "scrape"
+	a := newTestApp(t, cnf)
+
+	resp, body := a.do(t, http.MethodGet, "/metrics", "", map[string]string{fiber.HeaderAuthorization: "Bearer " + testAdminToken})
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode, "admin token does not open metrics")
+	assertErrorResponse(t, body, "Invalid metrics token")
+
+	resp, _ = a.do(t, http.MethodGet, "/metrics", "", map[string]string{fiber.HeaderAuthorization: "Bearer scrape"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestAppAdminRoutesDisabledWithoutToken(t *testing.T) {
+	cnf := testConfig()
+	cnf.Admin.Token = ""
+	a := newTestApp(t, cnf)
+
+	resp, body := a.do(t, http.MethodGet, "/admin/export", "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assertErrorResponse(t, body, "Cannot GET /admin/export")
+
+	resp, _ = a.do(t, http.MethodGet, "/metrics", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "metrics do not depend on the admin token")
+}
+
+func TestAppReadinessAfterShutdown(t *testing.T) {
+	a := newTestApp(t, testConfig())
+	a.checker.Shutdown()
+
+	resp, body := a.do(t, http.MethodGet, "/readyz", "", nil)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, health.StatusFail, decode[health.Report](t, body).Status)
+
+	resp, _ = a.do(t, http.MethodGet, "/healthz", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func newsTitles(newsList []models.NewsWithCategories) []string {
+	var titles []string
+	for _, n := range newsList {
+		titles = append(titles, n.Title)
+	}
+	return titles
+}
+
+// distinctTags возвращает JSON массив из n разных тегов
+func distinctTags(n int) string {
+	tags := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		tags = append(tags, fmt.Sprintf("tag-%d", i))
+	}
+	data, _ := json.Marshal(tags)
+	return string(data)
+}
+
+// multipartUpload собирает тело multipart/form-data с файлом в поле file и подписью в поле alt
+func multipartUpload(t *testing.T, filename string, data []byte, alt string) (string, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	if alt != "" {
+		require.NoError(t, writer.WriteField("alt", alt))
+	}
+	require.NoError(t, writer.Close())
+
+	return body.String(), writer.FormDataContentType()
+}
+
+func countFiles(t *testing.T, dir string) int {
+	t.Helper()
+
+	count := 0
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			count++
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return count
+}
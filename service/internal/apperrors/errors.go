@@ -8,6 +8,12 @@ var (
 	ErrInvalidID    = errors.New("invalid id format")
 	ErrInvalidBody  = errors.New("invalid request body")
 	ErrValidation   = errors.New("validation failed")
+	ErrRateLimited  = errors.New("rate limit exceeded")
+	ErrKeyReused    = errors.New("idempotency key reused")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrConflict     = errors.New("conflict")
+	ErrTooLarge     = errors.New("payload too large")
+	ErrMediaType    = errors.New("unsupported media type")
 )
 
 // AppError - кастомная ошибка с HTTP статусом
@@ -50,6 +56,54 @@ func NewValidation(message string) *AppError {
 	}
 }
 
+func NewUnprocessable(message string) *AppError {
+	return &AppError{
+		Err:        ErrKeyReused,
+		Message:    message,
+		StatusCode: 422,
+	}
+}
+
+func NewUnauthorized(message string) *AppError {
+	return &AppError{
+		Err:        ErrUnauthorized,
+		Message:    message,
+		StatusCode: 401,
+	}
+}
+
+func NewConflict(message string) *AppError {
+	return &AppError{
+		Err:        ErrConflict,
+		Message:    message,
+		StatusCode: 409,
+	}
+}
+
+func NewPayloadTooLarge(message string) *AppError {
+	return &AppError{
+		Err:        ErrTooLarge,
+		Message:    message,
+		StatusCode: 413,
+	}
+}
+
+func NewUnsupportedMediaType(message string) *AppError {
+	return &AppError{
+		Err:        ErrMediaType,
+		Message:    message,
+		StatusCode: 415,
+	}
+}
+
+func NewTooManyRequests(message string) *AppError {
+	return &AppError{
+		Err:        ErrRateLimited,
+		Message:    message,
+		StatusCode: 429,
+	}
+}
+
 func NewInternal(message string) *AppError {
 	return &AppError{
 		Err:        errors.New("internal error"),
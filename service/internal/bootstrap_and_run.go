@@ -2,74 +2,101 @@ package internal
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"service/internal/configs"
-	"service/internal/handlers"
-	handler "service/internal/handlers/news"
+	"service/internal/health"
 	"service/internal/repository"
 	"service/internal/service"
-	"service/pkg/db"
+	"service/pkg/blob"
+	"service/pkg/tracing"
 	"time"
 
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
-
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
 
 type Server struct {
-	log    *logrus.Logger
-	config configs.Config
-	app    *fiber.App
-	db     *sql.DB
+	log     *logrus.Logger
+	config  configs.Config
+	app     *fiber.App
+	storage storage
+	done    chan struct{}
+	health  *health.Checker
+
+	shutdownTracing func(context.Context) error
 }
 
-func NewServer(ctx context.Context, log *logrus.Logger) (*Server, error) {
-	cnf, err := configs.NewParsedConfig()
+func NewServer(ctx context.Context, cnf configs.Config, log *logrus.Logger) (*Server, error) {
+	shutdownTracing, err := tracing.Init(ctx, cnf.Tracing)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+		return nil, fmt.Errorf("failed to init tracing: %w", err)
 	}
 
-	database, reform, err := db.InitReformDB(cnf.Database)
+	done := make(chan struct{})
+	checker := health.NewChecker(time.Duration(cnf.Service.HealthCheckTimeout) * time.Second)
+
+	store, err := newStorage(ctx, cnf, checker, done, log)
 	if err != nil {
-		return nil, fmt.Errorf("failed to init reform db: %w", err)
+		if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+			log.Errorf("Error shutdown tracing: %v", shutdownErr)
+		}
+		return nil, err
 	}
 
-	repo := repository.NewNewsRepository(reform, log, ctx)
-	newsService := service.NewNewsService(repo, log)
-	newsHandler := handler.NewNewsHandler(newsService, log)
-	app := fiber.New(fiber.Config{
-		ErrorHandler: handlers.ErrorHandler(log),
-		ReadTimeout:  time.Duration(cnf.Service.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(cnf.Service.WriteTimeout) * time.Second,
+	media, err := blob.NewLocalStore(cnf.Media.Dir)
+	if err != nil {
+		// Stop для несозданного сервера не вызовут, уже открытые хранилище и трассировку закрываем здесь
+		close(done)
+		if closeErr := store.close(); closeErr != nil {
+			log.Errorf("Error close storage: %v", closeErr)
+		}
+		if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+			log.Errorf("Error shutdown tracing: %v", shutdownErr)
+		}
+		return nil, err
+	}
+
+	app := NewApp(AppDeps{
+		Config:      cnf,
+		NewsService: service.NewTracedNewsService(service.NewNewsService(store.news, media, log)),
+		Idempotency: store.idempotency,
+		Health:      checker,
+		Log:         log,
 	})
 
-	app.Use(recover.New(recover.Config{
-		EnableStackTrace: true,
-		StackTraceHandler: func(c *fiber.Ctx, e interface{}) {
-			log.WithFields(logrus.Fields{
-				"panic":  e,
-				"method": c.Method(),
-				"path":   c.Path(),
-			}).Error("Panic recovered")
-		},
-	}))
-
-	app.Use(logger.New(logger.Config{
-		Format: "[${time}] ${status} - ${method} ${path} ${latency}\n",
-	}))
-
-	handlers.SetupRoutes(app, newsHandler)
-
-	return &Server{
-		config: cnf,
-		app:    app,
-		db:     database,
-		log:    log,
-	}, nil
+	server := &Server{
+		config:  cnf,
+		app:     app,
+		storage: store,
+		log:     log,
+		done:    done,
+		health:  checker,
+
+		shutdownTracing: shutdownTracing,
+	}
+	go server.purgeIdempotencyKeys(store.idempotency)
+
+	return server, nil
+}
+
+// purgeIdempotencyKeys периодически удаляет просроченные ключи идемпотентности
+func (s *Server) purgeIdempotencyKeys(repo repository.IIdempotencyRepository) {
+	ticker := time.NewTicker(time.Duration(s.config.Idempotency.PurgeInterval) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			deleted, err := repo.PurgeExpired(context.Background())
+			if err != nil {
+				continue
+			}
+			s.log.WithField("deleted", deleted).Debug("Expired idempotency keys purged")
+		}
+	}
 }
 
 func (s *Server) Start() error {
@@ -84,6 +111,9 @@ func (s *Server) Start() error {
 
 func (s *Server) Stop(ctx context.Context) error {
 	s.log.Info("Start shutdown service")
+	s.health.Shutdown()
+	close(s.done)
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
@@ -96,11 +126,19 @@ func (s *Server) Stop(ctx context.Context) error {
 	})
 
 	g.Go(func() error {
-		if err := s.db.Close(); err != nil {
-			s.log.Errorf("Error close database: %v", err)
-			return fmt.Errorf("error close database: %w", err)
+		if err := s.storage.close(); err != nil {
+			s.log.Errorf("Error close storage: %v", err)
+			return err
+		}
+		s.log.Info("storage close successfully")
+		return nil
+	})
+
+	g.Go(func() error {
+		if err := s.shutdownTracing(ctx); err != nil {
+			s.log.Errorf("Error shutdown tracing: %v", err)
+			return fmt.Errorf("error shutdown tracing: %w", err)
 		}
-		s.log.Info("database close successfully")
 		return nil
 	})
 
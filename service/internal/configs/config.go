@@ -1,36 +1,180 @@
 package configs
 
-import (
-	"github.com/kelseyhightower/envconfig"
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 )
 
+// Config - конфигурация сервиса. Значения собираются слоями:
+// значения по умолчанию (Default), YAML файл, переменные окружения и флаги командной строки.
+// Поля с тегом secret не выводятся в --print-config.
 type Config struct {
-	Database Database
-	Service  Service
-	Port     string `envconfig:"PORT" default:":8080"`
+	// Storage - где хранятся новости: database (Database.Driver) или memory (без базы, данные живут до перезапуска)
+	Storage     string      `yaml:"storage" envconfig:"STORAGE"`
+	Database    Database    `yaml:"database"`
+	Service     Service     `yaml:"service"`
+	RateLimit   RateLimit   `yaml:"rate_limit"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Admin       Admin       `yaml:"admin"`
+	Metrics     Metrics     `yaml:"metrics"`
+	Clients     Clients     `yaml:"clients"`
+	Locale      Locale      `yaml:"locale"`
+	Media       Media       `yaml:"media"`
+	Port        string      `yaml:"port" envconfig:"PORT"`
 }
 
+// Database - подключение к базе. Для Postgres DSN, если задан, заменяет Host/Port/User/Password/Name и SSL настройки,
+// для SQLite DSN - путь к файлу базы. Время жизни и простоя соединений в минутах,
+// задержки повторного подключения в миллисекундах.
 type Database struct {
-	Host              string `envconfig:"DB_HOST" default:"localhost"`
-	Port              string `envconfig:"DB_PORT" required:"true"`
-	User              string `envconfig:"DB_USER" required:"true"`
-	Password          string `envconfig:"DB_PASSWORD" required:"true"`
-	Name              string `envconfig:"DB_NAME" required:"true"`
-	MaxOpenConnection int    `envconfig:"DB_MAX_OPEN_CONNECTION" default:"10"`
-	MaxLifeTime       int    `envconfig:"DB_MAX_OPEN_LIFE_TIME" default:"30"`
+	Driver            string `yaml:"driver" envconfig:"DB_DRIVER"`
+	DSN               string `yaml:"dsn" envconfig:"DB_DSN" secret:"true"`
+	Host              string `yaml:"host" envconfig:"DB_HOST"`
+	Port              string `yaml:"port" envconfig:"DB_PORT"`
+	User              string `yaml:"user" envconfig:"DB_USER"`
+	Password          string `yaml:"password" envconfig:"DB_PASSWORD" secret:"true"`
+	Name              string `yaml:"name" envconfig:"DB_NAME"`
+	SSLMode           string `yaml:"ssl_mode" envconfig:"DB_SSL_MODE"`
+	SSLRootCert       string `yaml:"ssl_root_cert" envconfig:"DB_SSL_ROOT_CERT"`
+	SSLCert           string `yaml:"ssl_cert" envconfig:"DB_SSL_CERT"`
+	SSLKey            string `yaml:"ssl_key" envconfig:"DB_SSL_KEY"`
+	MaxOpenConnection int    `yaml:"max_open_connection" envconfig:"DB_MAX_OPEN_CONNECTION"`
+	MaxIdleConnection int    `yaml:"max_idle_connection" envconfig:"DB_MAX_IDLE_CONNECTION"`
+	MaxLifeTime       int    `yaml:"max_life_time" envconfig:"DB_MAX_OPEN_LIFE_TIME"`
+	MaxIdleTime       int    `yaml:"max_idle_time" envconfig:"DB_MAX_IDLE_TIME"`
+	ConnectRetries    int    `yaml:"connect_retries" envconfig:"DB_CONNECT_RETRIES"`
+	ConnectRetryDelay int    `yaml:"connect_retry_delay" envconfig:"DB_CONNECT_RETRY_DELAY"`
+	ConnectRetryMax   int    `yaml:"connect_retry_max_delay" envconfig:"DB_CONNECT_RETRY_MAX_DELAY"`
+
+	LogMode            string `yaml:"log_mode" envconfig:"DB_LOG_MODE"`
+	SlowQueryThreshold int    `yaml:"slow_query_threshold" envconfig:"DB_SLOW_QUERY_THRESHOLD"`
+	LogRedactArgs      bool   `yaml:"log_redact_args" envconfig:"DB_LOG_REDACT_ARGS"`
+	AutoMigrate        bool   `yaml:"auto_migrate" envconfig:"DB_AUTO_MIGRATE"`
+
+	// Реплики для чтения: проверка доступности в секундах, окно чтения с мастера после записи в секундах
+	ReplicaDSNs          []string `yaml:"replica_dsns" envconfig:"DB_REPLICA_DSNS" secret:"true"`
+	ReplicaCheckInterval int      `yaml:"replica_check_interval" envconfig:"DB_REPLICA_CHECK_INTERVAL"`
+	ReplicaCheckTimeout  int      `yaml:"replica_check_timeout" envconfig:"DB_REPLICA_CHECK_TIMEOUT"`
+	PrimaryAfterWrite    int      `yaml:"primary_after_write" envconfig:"DB_PRIMARY_AFTER_WRITE"`
 }
 
 type Service struct {
-	ReadTimeout  int `envconfig:"SERVICE_READ_TIMEOUT" default:"10"`
-	WriteTimeout int `envconfig:"SERVICE_WRITE_TIMEOUT" default:"10"`
+	ReadTimeout        int `yaml:"read_timeout" envconfig:"SERVICE_READ_TIMEOUT"`
+	WriteTimeout       int `yaml:"write_timeout" envconfig:"SERVICE_WRITE_TIMEOUT"`
+	HealthCheckTimeout int `yaml:"health_check_timeout" envconfig:"SERVICE_HEALTH_CHECK_TIMEOUT"`
+	// BodyLimit - максимальный размер тела запроса в мегабайтах, ограничивает и файл импорта
+	BodyLimit int `yaml:"body_limit" envconfig:"SERVICE_BODY_LIMIT"`
 }
 
-func NewParsedConfig() (Config, error) {
-	var config Config
-	err := envconfig.Process("", &config)
-	if err != nil {
-		return config, err
-	}
+// RateLimit - лимиты запросов на клиента (API ключ или IP) отдельно для чтения и записи
+type RateLimit struct {
+	Enabled    bool    `yaml:"enabled" envconfig:"RATE_LIMIT_ENABLED"`
+	ReadRate   float64 `yaml:"read_rps" envconfig:"RATE_LIMIT_READ_RPS"`
+	ReadBurst  int     `yaml:"read_burst" envconfig:"RATE_LIMIT_READ_BURST"`
+	WriteRate  float64 `yaml:"write_rps" envconfig:"RATE_LIMIT_WRITE_RPS"`
+	WriteBurst int     `yaml:"write_burst" envconfig:"RATE_LIMIT_WRITE_BURST"`
+}
+
+// Idempotency - хранение ответов на запросы с заголовком Idempotency-Key
+type Idempotency struct {
+	KeyTTL        int `yaml:"key_ttl" envconfig:"IDEMPOTENCY_KEY_TTL"`
+	PurgeInterval int `yaml:"purge_interval" envconfig:"IDEMPOTENCY_PURGE_INTERVAL"`
+}
+
+// Tracing - экспорт трасс OpenTelemetry: none, stdout или otlp
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" envconfig:"TRACING_EXPORTER"`
+	Endpoint    string  `yaml:"otlp_endpoint" envconfig:"TRACING_OTLP_ENDPOINT"`
+	ServiceName string  `yaml:"service_name" envconfig:"TRACING_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" envconfig:"TRACING_SAMPLE_RATIO"`
+}
+
+// Admin - доступ к /admin/export и /admin/import по токену, без токена роуты выключены
+type Admin struct {
+	Token string `yaml:"token" envconfig:"ADMIN_TOKEN" secret:"true"`
+}
 
-	return config, nil
+// Metrics - токен для сбора /metrics. Пустой токен оставляет метрики открытыми:
+// их обычно закрывают сетью, а не доступом админа к выгрузке и загрузке новостей.
+type Metrics struct {
+	Token string `yaml:"token" envconfig:"METRICS_TOKEN" secret:"true"`
+}
+
+// Clients - API ключи клиентов. Запрос с известным ключом в X-API-Key считается запросом этого клиента
+// в лимитах и ключах идемпотентности, остальные клиенты различаются по IP.
+type Clients struct {
+	APIKeys []string `yaml:"api_keys" envconfig:"CLIENT_API_KEYS" secret:"true"`
+}
+
+// Locale - язык основного текста новостей, на него откатывается выбор перевода при чтении
+type Locale struct {
+	Default string `yaml:"default" envconfig:"DEFAULT_LOCALE"`
+}
+
+// Media - вложения новостей: каталог, где локальное хранилище держит файлы, и предельный размер файла в мегабайтах
+type Media struct {
+	Dir     string `yaml:"dir" envconfig:"MEDIA_DIR"`
+	MaxSize int    `yaml:"max_size" envconfig:"MEDIA_MAX_SIZE"`
+}
+
+// Default возвращает значения по умолчанию - нижний слой конфигурации
+func Default() Config {
+	return Config{
+		Storage: StorageDatabase,
+		Database: Database{
+			Driver:            DriverPostgres,
+			Host:              "localhost",
+			SSLMode:           "disable",
+			MaxOpenConnection: 10,
+			MaxIdleConnection: 5,
+			MaxLifeTime:       30,
+			MaxIdleTime:       5,
+			ConnectRetries:    5,
+			ConnectRetryDelay: 500,
+			ConnectRetryMax:   10000,
+
+			LogMode:            "slow",
+			SlowQueryThreshold: 200,
+			LogRedactArgs:      true,
+			AutoMigrate:        true,
+
+			ReplicaCheckInterval: 5,
+			ReplicaCheckTimeout:  2,
+			PrimaryAfterWrite:    5,
+		},
+		Service: Service{
+			ReadTimeout:        10,
+			WriteTimeout:       10,
+			HealthCheckTimeout: 2,
+			BodyLimit:          4,
+		},
+		RateLimit: RateLimit{
+			Enabled:    true,
+			ReadRate:   20,
+			ReadBurst:  40,
+			WriteRate:  1,
+			WriteBurst: 5,
+		},
+		Idempotency: Idempotency{
+			KeyTTL:        24,
+			PurgeInterval: 60,
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			ServiceName: "news-service",
+			SampleRatio: 1,
+		},
+		Locale: Locale{
+			Default: "ru",
+		},
+		Media: Media{
+			Dir:     "data/media",
+			MaxSize: 3,
+		},
+		Port: "8080",
+	}
 }
@@ -0,0 +1,212 @@
+package configs
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v3"
+)
+
+const envConfigFile = "CONFIG_FILE"
+
+// Options - флаги запуска, которые управляют загрузкой, но сами в конфиг не входят
+// Args - позиционные аргументы после флагов, например подкоманда cmd/migrate
+type Options struct {
+	ConfigFile  string
+	PrintConfig bool
+	Args        []string
+}
+
+// Load собирает конфиг из значений по умолчанию, YAML файла (--config или CONFIG_FILE),
+// переменных окружения и флагов. Каждый следующий слой перекрывает предыдущий.
+// Для каждого поля есть флаг, имя которого получено из имени переменной окружения:
+// DB_HOST -> --db-host.
+func Load(args []string) (Config, Options, error) {
+	cnf := Default()
+
+	fs := flag.NewFlagSet("service", flag.ContinueOnError)
+	var opts Options
+	fs.StringVar(&opts.ConfigFile, "config", os.Getenv(envConfigFile), "path to YAML config file")
+	fs.BoolVar(&opts.PrintConfig, "print-config", false, "print effective config with secrets redacted and exit")
+
+	overrides := make(map[string]string)
+	for _, field := range configFields(&cnf) {
+		name := flagName(field.env)
+		fs.Func(name, "overrides "+field.env, func(value string) error {
+			overrides[name] = value
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return cnf, opts, err
+	}
+	opts.Args = fs.Args()
+
+	if opts.ConfigFile != "" {
+		if err := loadFile(opts.ConfigFile, &cnf); err != nil {
+			return cnf, opts, err
+		}
+	}
+
+	// В структуре нет тегов default и required, поэтому envconfig трогает только заданные переменные
+	if err := envconfig.Process("", &cnf); err != nil {
+		return cnf, opts, fmt.Errorf("failed to read env: %w", err)
+	}
+
+	for _, field := range configFields(&cnf) {
+		value, ok := overrides[flagName(field.env)]
+		if !ok {
+			continue
+		}
+		if err := setValue(field.value, value); err != nil {
+			return cnf, opts, fmt.Errorf("invalid value %q for flag --%s: %w", value, flagName(field.env), err)
+		}
+	}
+
+	if err := cnf.Validate(); err != nil {
+		return cnf, opts, err
+	}
+
+	return cnf, opts, nil
+}
+
+// NewParsedConfig читает конфиг без флагов командной строки
+func NewParsedConfig() (Config, error) {
+	cnf, _, err := Load(nil)
+	return cnf, err
+}
+
+func loadFile(path string, cnf *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(cnf); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Redacted возвращает копию конфига со скрытыми секретами
+func (c Config) Redacted() Config {
+	redacted := c
+	for _, field := range configFields(&redacted) {
+		if !field.secret {
+			continue
+		}
+		switch field.value.Kind() {
+		case reflect.String:
+			if field.value.String() != "" {
+				field.value.SetString("******")
+			}
+		case reflect.Slice:
+			masked := make([]string, field.value.Len())
+			for i := range masked {
+				masked[i] = "******"
+			}
+			field.value.Set(reflect.ValueOf(masked))
+		}
+	}
+	return redacted
+}
+
+// YAML сериализует конфиг в том же формате, что читается из файла
+func (c Config) YAML() (string, error) {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+type configField struct {
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+// configFields обходит все листовые поля конфига с тегом envconfig
+func configFields(cnf *Config) []configField {
+	var fields []configField
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			value := v.Field(i)
+			if field.Type.Kind() == reflect.Struct {
+				walk(value)
+				continue
+			}
+
+			env := field.Tag.Get("envconfig")
+			if env == "" {
+				continue
+			}
+			fields = append(fields, configField{
+				env:    env,
+				secret: field.Tag.Get("secret") == "true",
+				value:  value,
+			})
+		}
+	}
+	walk(reflect.ValueOf(cnf).Elem())
+
+	return fields
+}
+
+func flagName(env string) string {
+	return strings.ToLower(strings.ReplaceAll(env, "_", "-"))
+}
+
+func setValue(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
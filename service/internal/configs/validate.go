@@ -0,0 +1,111 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/text/language"
+)
+
+// Validate проверяет значения по смыслу, чтобы ошибка конфигурации падала при старте, а не в работе
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validPort(c.Port), "port must be a number between 1 and 65535, got %q", c.Port)
+
+	// Хранилище в базе одно для всех драйверов, сама база выбирается DB_DRIVER
+	if c.Storage == DriverPostgres {
+		check(false, "storage %q is not supported, use %q with database driver %q", c.Storage, StorageDatabase, DriverPostgres)
+	} else {
+		check(oneOf(c.Storage, StorageDatabase, StorageMemory), "storage must be one of database, memory, got %q", c.Storage)
+	}
+
+	// Настройки базы проверяем, только если она используется
+	if c.Storage == StorageDatabase {
+		check(oneOf(c.Database.Driver, DriverPostgres, DriverSQLite), "database driver must be one of postgres, sqlite, got %q", c.Database.Driver)
+		switch {
+		case c.Database.Driver == DriverSQLite:
+			check(c.Database.DSN != "", "database dsn (path to the sqlite file) is required for sqlite driver")
+			check(len(c.Database.ReplicaDSNs) == 0, "database replicas are not supported by sqlite driver")
+		case c.Database.DSN == "":
+			check(c.Database.Host != "", "database host is required")
+			check(validPort(c.Database.Port), "database port must be a number between 1 and 65535, got %q", c.Database.Port)
+			check(c.Database.User != "", "database user is required")
+			check(c.Database.Name != "", "database name is required")
+			check(oneOf(c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+				"database ssl mode must be one of disable, allow, prefer, require, verify-ca, verify-full, got %q", c.Database.SSLMode)
+			check((c.Database.SSLCert == "") == (c.Database.SSLKey == ""), "database ssl cert and key must be set together")
+		}
+		check(c.Database.MaxOpenConnection > 0, "database max open connection must be positive")
+		check(c.Database.MaxIdleConnection >= 0 && c.Database.MaxIdleConnection <= c.Database.MaxOpenConnection,
+			"database max idle connection must be between 0 and max open connection")
+		check(c.Database.MaxLifeTime > 0, "database max life time must be positive")
+		check(c.Database.MaxIdleTime > 0, "database max idle time must be positive")
+		check(c.Database.ConnectRetries >= 0, "database connect retries cannot be negative")
+		check(c.Database.ConnectRetryDelay > 0, "database connect retry delay must be positive")
+		check(c.Database.ConnectRetryMax >= c.Database.ConnectRetryDelay, "database connect retry max delay must not be less than retry delay")
+		check(c.Database.ReplicaCheckInterval > 0, "database replica check interval must be positive")
+		check(c.Database.ReplicaCheckTimeout > 0, "database replica check timeout must be positive")
+		check(c.Database.PrimaryAfterWrite >= 0, "database primary after write window cannot be negative")
+		for i, dsn := range c.Database.ReplicaDSNs {
+			check(dsn != "", "database replica dsn %d is empty", i)
+		}
+		check(oneOf(c.Database.LogMode, "off", "slow", "all"), "database log mode must be one of off, slow, all, got %q", c.Database.LogMode)
+		check(c.Database.SlowQueryThreshold >= 0, "database slow query threshold cannot be negative")
+	}
+
+	check(c.Service.ReadTimeout > 0, "service read timeout must be positive")
+	check(c.Service.WriteTimeout > 0, "service write timeout must be positive")
+	check(c.Service.HealthCheckTimeout > 0, "service health check timeout must be positive")
+	check(c.Service.BodyLimit > 0, "service body limit must be positive")
+
+	if c.RateLimit.Enabled {
+		check(c.RateLimit.ReadRate > 0, "rate limit read rps must be positive")
+		check(c.RateLimit.ReadBurst > 0, "rate limit read burst must be positive")
+		check(c.RateLimit.WriteRate > 0, "rate limit write rps must be positive")
+		check(c.RateLimit.WriteBurst > 0, "rate limit write burst must be positive")
+	}
+
+	check(c.Idempotency.KeyTTL > 0, "idempotency key ttl must be positive")
+	check(c.Idempotency.PurgeInterval > 0, "idempotency purge interval must be positive")
+
+	check(oneOf(c.Tracing.Exporter, "none", "stdout", "otlp"), "tracing exporter must be one of none, stdout, otlp, got %q", c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing sample ratio must be between 0 and 1")
+
+	tag, err := language.Parse(c.Locale.Default)
+	check(err == nil && tag != language.Und, "default locale must be a valid BCP 47 language tag, got %q", c.Locale.Default)
+
+	for i, key := range c.Clients.APIKeys {
+		check(key != "", "client api key %d is empty", i)
+	}
+
+	check(c.Media.Dir != "", "media dir is required")
+	check(c.Media.MaxSize > 0, "media max size must be positive")
+	// Файл приходит в multipart вместе с заголовками частей, поэтому лимит тела должен быть больше
+	check(c.Media.MaxSize < c.Service.BodyLimit, "media max size must be less than service body limit")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+// Package content превращает текст новости в безопасный HTML для фронтендов.
+// Markdown рендерится goldmark, затем любой HTML проходит через allowlist bluemonday:
+// скрипты, обработчики событий и небезопасные ссылки (javascript:, data: и т.п.) вырезаются.
+package content
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+var (
+	Formats = []string{FormatPlain, FormatMarkdown, FormatHTML}
+
+	// Сырой HTML внутри Markdown пропускаем в рендер: его всё равно чистит policy
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	// UGCPolicy допускает разметку текста, таблицы, ссылки и картинки только с безопасными схемами
+	// и добавляет rel="nofollow" к ссылкам
+	policy = bluemonday.UGCPolicy()
+
+	// Совпадает с заполнением content_html в миграции для уже существующих новостей
+	plainEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\n", "<br>\n")
+)
+
+func IsValidFormat(format string) bool {
+	return slices.Contains(Formats, format)
+}
+
+// Render возвращает санитизированный HTML текста в формате format.
+// Неизвестный формат считается простым текстом.
+func Render(format, source string) string {
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		// Запись в bytes.Buffer не возвращает ошибок, а парсер Markdown принимает любой текст
+		_ = markdown.Convert([]byte(source), &buf)
+		return policy.Sanitize(buf.String())
+	case FormatHTML:
+		return policy.Sanitize(source)
+	default:
+		return "<p>" + plainEscaper.Replace(source) + "</p>"
+	}
+}
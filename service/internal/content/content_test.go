@@ -0,0 +1,79 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		source  string
+		want    string
+		notWant []string
+	}{
+		{
+			name:   "plain is escaped",
+			format: FormatPlain,
+			source: "a < b & c\nnext <b>line</b>",
+			want:   "<p>a &lt; b &amp; c<br>\nnext &lt;b&gt;line&lt;/b&gt;</p>",
+		},
+		{
+			name:   "unknown format is plain",
+			format: "",
+			source: "<i>x</i>",
+			want:   "<p>&lt;i&gt;x&lt;/i&gt;</p>",
+		},
+		{
+			name:   "markdown",
+			format: FormatMarkdown,
+			source: "# Title\n\n**bold** and [link](https://example.com)",
+			want:   "<h1>Title</h1>\n<p><strong>bold</strong> and <a href=\"https://example.com\" rel=\"nofollow\">link</a></p>\n",
+		},
+		{
+			name:    "markdown raw html is sanitized",
+			format:  FormatMarkdown,
+			source:  "text <script>alert(1)</script> <img src=\"x.png\" onerror=\"alert(1)\">",
+			notWant: []string{"<script", "alert", "onerror"},
+		},
+		{
+			name:    "markdown javascript link",
+			format:  FormatMarkdown,
+			source:  "[click](javascript:alert(1))",
+			notWant: []string{"javascript:", "href"},
+		},
+		{
+			name:   "html keeps formatting",
+			format: FormatHTML,
+			source: "<p>Hello <em>world</em></p>",
+			want:   "<p>Hello <em>world</em></p>",
+		},
+		{
+			name:    "html scripts and handlers",
+			format:  FormatHTML,
+			source:  "<p onclick=\"steal()\">text</p><script>steal()</script><iframe src=\"https://evil.example\"></iframe>",
+			want:    "<p>text</p>",
+			notWant: []string{"onclick", "<script", "<iframe"},
+		},
+		{
+			name:    "html unsafe urls",
+			format:  FormatHTML,
+			source:  "<a href=\"javascript:steal()\">a</a><img src=\"data:text/html;base64,PHNjcmlwdD4=\">",
+			notWant: []string{"javascript:", "data:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.format, tt.source)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, got)
+			}
+			for _, s := range tt.notWant {
+				assert.NotContains(t, got, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// AccessLog пишет одну структурированную запись logrus на каждый запрос
+func AccessLog(log *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		err := c.Next()
+
+		status := responseStatus(c, err)
+		entry := log.WithContext(c.UserContext()).WithFields(logrus.Fields{
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"route":      c.Route().Path,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"ip":         c.IP(),
+			"user_agent": c.Get(fiber.HeaderUserAgent),
+			"bytes":      len(c.Response().Body()),
+		})
+
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			entry.Error("Request completed")
+		case status >= fiber.StatusBadRequest:
+			entry.Warn("Request completed")
+		default:
+			entry.Info("Request completed")
+		}
+
+		return err
+	}
+}
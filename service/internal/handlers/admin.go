@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"service/internal/apperrors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminAuth пускает к служебным роутам только запросы с заголовком Authorization: Bearer <token>
+func AdminAuth(token string) fiber.Handler {
+	return bearerAuth(token, "Invalid admin token")
+}
+
+// MetricsAuth пускает к /metrics только сборщик метрик со своим токеном в Authorization: Bearer <token>
+func MetricsAuth(token string) fiber.Handler {
+	return bearerAuth(token, "Invalid metrics token")
+}
+
+func bearerAuth(token, message string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		given, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			return apperrors.NewUnauthorized(message)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+// ClientID возвращает функцию, определяющую клиента запроса для лимитов и ключей идемпотентности:
+// "key:<хеш>" для API ключа из apiKeys, иначе "ip:<IP>". Неизвестный ключ не учитывается - иначе клиент
+// обходил бы лимит, присылая каждый раз новый ключ. Сам ключ в идентификатор не попадает, только его хеш.
+func ClientID(apiKeys []string) func(c *fiber.Ctx) string {
+	known := make(map[string]string, len(apiKeys))
+	for _, key := range apiKeys {
+		sum := sha256.Sum256([]byte(key))
+		known[key] = "key:" + hex.EncodeToString(sum[:8])
+	}
+
+	return func(c *fiber.Ctx) string {
+		if id, ok := known[c.Get(HeaderAPIKey)]; ok {
+			return id
+		}
+		return "ip:" + c.IP()
+	}
+}
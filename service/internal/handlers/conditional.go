@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConditionalGet - middleware для условных GET/HEAD запросов.
+// Считает сильный ETag по телу успешного ответа (в теле уже есть Version и UpdatedAt новостей,
+// поэтому любое изменение статьи меняет ETag) и отвечает 304 Not Modified,
+// если клиент прислал совпадающий If-None-Match или If-Modified-Since не раньше Last-Modified.
+// Last-Modified выставляет сам обработчик.
+func ConditionalGet() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		etag := strongETag(c.Response().Body())
+		c.Set(fiber.HeaderETag, etag)
+
+		// If-None-Match приоритетнее If-Modified-Since (RFC 9110, 13.2.2)
+		if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+			if etagMatches(ifNoneMatch, etag) {
+				return notModified(c)
+			}
+			return nil
+		}
+
+		if ifModifiedSince := c.Get(fiber.HeaderIfModifiedSince); ifModifiedSince != "" {
+			lastModified := string(c.Response().Header.Peek(fiber.HeaderLastModified))
+			if notModifiedSince(ifModifiedSince, lastModified) {
+				return notModified(c)
+			}
+		}
+
+		return nil
+	}
+}
+
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches сравнивает ETag слабым сравнением, как того требует If-None-Match
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func notModifiedSince(ifModifiedSince, lastModified string) bool {
+	if lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+
+	return !modified.After(since)
+}
+
+func notModified(c *fiber.Ctx) error {
+	c.Response().ResetBody()
+	c.Response().Header.Del(fiber.HeaderContentType)
+	c.Status(fiber.StatusNotModified)
+	return nil
+}
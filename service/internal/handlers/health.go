@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"service/internal/health"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Liveness отвечает, пока процесс жив и обрабатывает запросы
+func Liveness() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(health.Report{Status: health.StatusOK})
+	}
+}
+
+// Readiness проверяет зависимости сервиса и отвечает 503, если хотя бы одна недоступна
+func Readiness(checker *health.Checker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report := checker.Run(c.UserContext())
+
+		status := fiber.StatusOK
+		if report.Status != health.StatusOK {
+			status = fiber.StatusServiceUnavailable
+		}
+
+		return c.Status(status).JSON(report)
+	}
+}
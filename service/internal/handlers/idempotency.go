@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"service/internal/apperrors"
+	"service/internal/models"
+	"service/internal/repository"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// Idempotency делает запросы на запись с заголовком Idempotency-Key идемпотентными.
+// Первый ответ сохраняется вместе с хэшем запроса, повтор с тем же ключом получает сохранённый ответ,
+// а тот же ключ с другим запросом - 422. Ответы 5xx и ошибки не сохраняются, такой запрос можно повторить.
+// Ключи принадлежат клиенту из clientID: чужой ключ не отдаёт сохранённый ответ другого клиента.
+func Idempotency(repo repository.IIdempotencyRepository, clientID func(c *fiber.Ctx) string, ttl time.Duration, log *logrus.Logger) fiber.Handler {
+	locks := newKeyLocks()
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return apperrors.NewBadRequest("Idempotency-Key is too long")
+		}
+
+		client := clientID(c)
+		hash := requestHash(c)
+
+		// Внутри процесса дубликаты ждут на мьютексе и получают сохранённый ответ,
+		// а дубликат на другом экземпляре получит 409, пока ключ занят
+		unlock := locks.lock(client + " " + key)
+		defer unlock()
+
+		lock, err := repo.Acquire(c.UserContext(), client, key, hash, ttl)
+		if err != nil {
+			return err
+		}
+
+		if stored := lock.Stored(); stored != nil {
+			lock.Abort()
+			if stored.RequestHash != hash {
+				return apperrors.NewUnprocessable("Idempotency-Key was already used with a different request")
+			}
+
+			c.Set(HeaderIdempotentReplayed, "true")
+			c.Set(fiber.HeaderContentType, stored.ContentType)
+			return c.Status(stored.StatusCode).Send(stored.ResponseBody)
+		}
+
+		if err = c.Next(); err != nil {
+			lock.Abort()
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			lock.Abort()
+			return nil
+		}
+
+		err = lock.Complete(models.IdempotencyRecord{
+			RequestHash:  hash,
+			StatusCode:   status,
+			ContentType:  string(c.Response().Header.ContentType()),
+			ResponseBody: c.Response().Body(),
+		})
+		if err != nil {
+			// Ответ уже сформирован, клиенту его отдаём, просто повтор выполнится заново
+			log.WithContext(c.UserContext()).WithError(err).WithField("idempotency_key", key).Error("Failed to store idempotent response")
+		}
+
+		return nil
+	}
+}
+
+func requestHash(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Path()))
+	h.Write([]byte{0})
+	h.Write(c.Request().URI().QueryString())
+	h.Write([]byte{0})
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// keyLocks - мьютексы по ключу, которые удаляются, когда их никто не ждёт
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+func newKeyLocks() *keyLocks {
+	return &keyLocks{locks: make(map[string]*keyLock)}
+}
+
+func (k *keyLocks) lock(key string) func() {
+	k.mu.Lock()
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyLock{}
+		k.locks[key] = l
+	}
+	l.waiters++
+	k.mu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		k.mu.Lock()
+		l.waiters--
+		if l.waiters == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
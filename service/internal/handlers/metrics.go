@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"service/internal/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics считает запросы и их длительность по шаблону роута, а не по сырому пути,
+// чтобы id в пути не раздували количество серий
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		err := c.Next()
+
+		route := c.Route().Path
+		status := strconv.Itoa(responseStatus(c, err))
+
+		metrics.HTTPRequests.WithLabelValues(c.Method(), route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Method(), route, status).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// MetricsHandler отдаёт метрики в формате Prometheus
+func MetricsHandler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.Handler())
+}
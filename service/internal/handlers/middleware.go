@@ -30,14 +30,14 @@ func ErrorHandler(log *logrus.Logger) fiber.ErrorHandler {
 			// Логируем в зависимости от типа
 			if code >= 500 {
 				// Серверные ошибки - ERROR уровень
-				log.WithFields(logrus.Fields{
+				log.WithContext(c.UserContext()).WithFields(logrus.Fields{
 					"method": c.Method(),
 					"path":   c.Path(),
 					"error":  err.Error(),
 				}).Error("Internal server error")
 			} else {
 				// Клиентские ошибки - WARN уровень
-				log.WithFields(logrus.Fields{
+				log.WithContext(c.UserContext()).WithFields(logrus.Fields{
 					"method": c.Method(),
 					"path":   c.Path(),
 					"error":  message,
@@ -52,7 +52,7 @@ func ErrorHandler(log *logrus.Logger) fiber.ErrorHandler {
 			}
 
 			// Логируем неожиданные ошибки
-			log.WithFields(logrus.Fields{
+			log.WithContext(c.UserContext()).WithFields(logrus.Fields{
 				"method": c.Method(),
 				"path":   c.Path(),
 				"error":  err.Error(),
@@ -66,3 +66,25 @@ func ErrorHandler(log *logrus.Logger) fiber.ErrorHandler {
 		})
 	}
 }
+
+// errorStatus - статус, которым ErrorHandler ответит на ошибку
+func errorStatus(err error) int {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.StatusCode
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// responseStatus - статус ответа для middleware перед ErrorHandler: ошибку он ещё не обработал,
+// поэтому статус берётся из неё, а не из ответа
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err != nil {
+		return errorStatus(err)
+	}
+	return c.Response().StatusCode()
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"service/internal/apperrors"
+	"service/internal/models"
+	"service/internal/newsio"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportNews потоково выгружает все новости в JSON Lines или CSV.
+// Тело пишется уже после выхода из обработчика, поэтому ошибку посреди выгрузки
+// можно только залогировать: клиент получит оборванный файл.
+func (h *NewsHandler) ExportNews(c *fiber.Ctx) error {
+	format := c.Query("format", newsio.FormatJSONL)
+	if format != newsio.FormatJSONL && format != newsio.FormatCSV {
+		return apperrors.NewBadRequest(newsio.ErrUnknownFormat.Error())
+	}
+
+	ctx := c.UserContext()
+	fctx := c.Context()
+	writeTimeout := c.App().Config().WriteTimeout
+
+	c.Set(fiber.HeaderContentType, newsio.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="news.%s"`, format))
+
+	fctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		encoder, _ := newsio.NewEncoder(w, format)
+
+		// WriteTimeout сервера рассчитан на обычные ответы, а выгрузка может идти дольше:
+		// продлеваем дедлайн, пока клиент читает
+		extendedAt := time.Now()
+		err := h.service.ExportNews(ctx, func(news models.NewsWithCategories) error {
+			if writeTimeout > 0 && time.Since(extendedAt) > writeTimeout/2 {
+				extendedAt = time.Now()
+				if err := fctx.Conn().SetWriteDeadline(extendedAt.Add(writeTimeout)); err != nil {
+					return err
+				}
+			}
+			return encoder.Encode(news)
+		})
+		if err == nil {
+			err = encoder.Flush()
+		}
+		if err != nil {
+			h.log.WithContext(ctx).WithError(err).Error("Failed to export news")
+		}
+	})
+
+	return nil
+}
+
+// ImportNews загружает новости из JSON Lines или CSV. Каждая строка проверяется как форма создания,
+// строки с внешним id обновляют ранее загруженные новости. С dry_run=true база не меняется.
+// В ответе счётчики и ошибки по номерам строк, невалидные строки не мешают загрузке остальных.
+func (h *NewsHandler) ImportNews(c *fiber.Ctx) error {
+	format := c.Query("format", newsio.FormatJSONL)
+	if format != newsio.FormatJSONL && format != newsio.FormatCSV {
+		return apperrors.NewBadRequest(newsio.ErrUnknownFormat.Error())
+	}
+	dryRun := c.QueryBool("dry_run", false)
+
+	report, err := newsio.Import(c.UserContext(), bytes.NewReader(c.Body()), format, dryRun, h.service.ImportNews)
+	var inputErr *newsio.InputError
+	if errors.As(err, &inputErr) {
+		return apperrors.NewBadRequest(inputErr.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
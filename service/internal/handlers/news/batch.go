@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"service/internal/apperrors"
+	"service/internal/models"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxBatchSize = 500
+
+type NewsBatchResponse struct {
+	Success bool
+	Results []models.NewsBatchResult
+}
+
+// BatchNews создаёт и редактирует новости пакетом.
+// С atomic=true пакет выполняется целиком или не выполняется вовсе,
+// иначе каждая операция выполняется независимо и получает свой статус.
+func (h *NewsHandler) BatchNews(c *fiber.Ctx) error {
+	atomic := c.QueryBool("atomic", false)
+
+	var form models.NewsBatchForm
+	if err := c.BodyParser(&form); err != nil {
+		return apperrors.NewBadRequest("Invalid request body")
+	}
+
+	if len(form.Operations) == 0 {
+		return apperrors.NewValidation("operations cannot be empty")
+	}
+	if len(form.Operations) > maxBatchSize {
+		return apperrors.NewValidation(fmt.Sprintf("operations length must be less or equal to %d", maxBatchSize))
+	}
+
+	items := make([]models.NewsBatchItem, 0, len(form.Operations))
+	var invalid []models.NewsBatchResult
+	for i, operation := range form.Operations {
+		item, err := parseBatchOperation(i, operation)
+		if err != nil {
+			invalid = append(invalid, models.NewsBatchResult{
+				Index:  i,
+				Op:     operation.Op,
+				Id:     operation.ID,
+				Status: fiber.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		items = append(items, item)
+	}
+
+	// В атомарном режиме невалидная операция отменяет весь пакет ещё до базы
+	if atomic && len(invalid) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(NewsBatchResponse{
+			Success: false,
+			Results: invalid,
+		})
+	}
+
+	results, err := h.service.BatchNews(c.UserContext(), items, atomic)
+	if err != nil {
+		return err
+	}
+
+	results = append(results, invalid...)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+
+	success := true
+	for _, result := range results {
+		if !result.Success {
+			success = false
+			break
+		}
+	}
+
+	status := fiber.StatusOK
+	if atomic && !success {
+		status = fiber.StatusConflict
+	}
+
+	return c.Status(status).JSON(NewsBatchResponse{
+		Success: success,
+		Results: results,
+	})
+}
+
+func parseBatchOperation(index int, operation models.NewsBatchOperation) (models.NewsBatchItem, error) {
+	item := models.NewsBatchItem{
+		Index:  index,
+		Op:     operation.Op,
+		NewsID: operation.ID,
+	}
+
+	if len(operation.Data) == 0 {
+		return item, models.ErrBodyEmpty
+	}
+
+	switch operation.Op {
+	case models.BatchOpCreate:
+		var createForm models.NewsCreateForm
+		if err := json.Unmarshal(operation.Data, &createForm); err != nil {
+			return item, apperrors.ErrInvalidBody
+		}
+
+		createForm.Normalize()
+		if err := createForm.Validate(); err != nil {
+			return item, err
+		}
+		item.Create = &createForm
+	case models.BatchOpEdit:
+		if operation.ID <= 0 {
+			return item, apperrors.ErrInvalidID
+		}
+
+		var editForm models.NewsEditForm
+		if err := json.Unmarshal(operation.Data, &editForm); err != nil {
+			return item, apperrors.ErrInvalidBody
+		}
+
+		editForm.Normalize()
+		if err := editForm.Validate(); err != nil {
+			return item, err
+		}
+		item.Edit = &editForm
+	default:
+		return item, fmt.Errorf("op must be one of %q, %q", models.BatchOpCreate, models.BatchOpEdit)
+	}
+
+	return item, nil
+}
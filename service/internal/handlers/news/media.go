@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"service/internal/apperrors"
+	"service/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// mediaCacheControl - файл вложения по id не меняется, клиенты и прокси могут хранить его сколько угодно
+const mediaCacheControl = "public, max-age=31536000, immutable"
+
+type MediaResponse struct {
+	Success bool
+	Media   models.Media
+}
+
+// UploadMedia прикрепляет к новости файл из поля file формы multipart/form-data, подпись к нему - поле alt
+func (h *NewsHandler) UploadMedia(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperrors.NewBadRequest("Invalid ID format")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return apperrors.NewBadRequest("file is required in multipart/form-data body")
+	}
+	if fileHeader.Size > h.maxMediaSize {
+		return apperrors.NewPayloadTooLarge(fmt.Sprintf("file size must be less or equal to %d MB", h.maxMediaSize>>20))
+	}
+
+	upload := models.MediaUpload{AltText: c.FormValue("alt")}
+	upload.Normalize()
+	if err = upload.Validate(); err != nil {
+		return apperrors.NewValidation(err.Error())
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer file.Close()
+	upload.Body = file
+
+	media, err := h.service.AttachMedia(c.UserContext(), id, upload)
+	if err != nil {
+		return err
+	}
+
+	c.Location(fmt.Sprintf("/media/%d", media.ID))
+	return c.Status(fiber.StatusCreated).JSON(MediaResponse{
+		Success: true,
+		Media:   media,
+	})
+}
+
+// GetMedia отдаёт файл вложения потоком, не читая его в память. ETag - контрольная сумма содержимого.
+// Условные заголовки и Range обрабатываются как в http.ServeContent, но из нескольких диапазонов
+// отдаётся весь файл: RFC 9110 разрешает игнорировать Range.
+func (h *NewsHandler) GetMedia(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperrors.NewBadRequest("Invalid ID format")
+	}
+
+	media, file, err := h.service.OpenMedia(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	etag := `"` + media.Checksum + `"`
+	lastModified := media.CreatedAt.UTC().Truncate(time.Second)
+
+	c.Set(fiber.HeaderContentType, media.MimeType)
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, lastModified.Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, mediaCacheControl)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+
+	if notModified(c, etag, lastModified) {
+		file.Close()
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	start, length := int64(0), media.Size
+	if c.Get(fiber.HeaderRange) != "" && ifRangeMatches(c.Get(fiber.HeaderIfRange), etag, lastModified) {
+		ranges, err := c.Range(int(media.Size))
+		switch {
+		case errors.Is(err, fiber.ErrRangeUnsatisfiable):
+			file.Close()
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", media.Size))
+			return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+		case err == nil && ranges.Type == "bytes" && len(ranges.Ranges) == 1:
+			start, length = int64(ranges.Ranges[0].Start), int64(ranges.Ranges[0].End-ranges.Ranges[0].Start+1)
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", ranges.Ranges[0].Start, ranges.Ranges[0].End, media.Size))
+			c.Status(fiber.StatusPartialContent)
+		}
+	}
+
+	if _, err = file.Seek(start, io.SeekStart); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to seek media: %w", err)
+	}
+
+	// fasthttp закрывает поток тела, когда ответ отправлен
+	return c.SendStream(mediaStream{Reader: io.LimitReader(file, length), Closer: file}, int(length))
+}
+
+type mediaStream struct {
+	io.Reader
+	io.Closer
+}
+
+// notModified - ответить 304 по If-None-Match, а без него по If-Modified-Since (RFC 9110, 13.2.2)
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	return err == nil && !lastModified.After(since)
+}
+
+// ifRangeMatches - Range применяется, если If-Range нет или он совпадает с ETag или Last-Modified
+func ifRangeMatches(ifRange, etag string, lastModified time.Time) bool {
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) {
+		return ifRange == etag
+	}
+	since, err := http.ParseTime(ifRange)
+	return err == nil && lastModified.Equal(since)
+}
@@ -2,27 +2,46 @@
 package handlers
 
 import (
+	"net/http"
+	"net/url"
 	"service/internal/apperrors"
+	"service/internal/content"
 	"service/internal/models"
 	"service/internal/service"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 )
 
 type NewsHandler struct {
-	service service.INewsService
-	log     *logrus.Logger
+	service       service.INewsService
+	defaultLocale string
+	maxMediaSize  int64
+	log           *logrus.Logger
 }
 
-func NewNewsHandler(service service.INewsService, log *logrus.Logger) NewsHandler {
+// NewNewsHandler создаёт обработчик новостей, defaultLocale - язык основного текста новостей,
+// maxMediaSize - предельный размер загружаемого файла в байтах
+func NewNewsHandler(service service.INewsService, defaultLocale string, maxMediaSize int64, log *logrus.Logger) NewsHandler {
+	if locale, err := models.ParseLocale(defaultLocale); err == nil {
+		defaultLocale = locale
+	}
+
 	return NewsHandler{
-		service: service,
-		log:     log,
+		service:       service,
+		defaultLocale: defaultLocale,
+		maxMediaSize:  maxMediaSize,
+		log:           log,
 	}
 }
 
+const (
+	RenderRaw  = "raw"
+	RenderHTML = "html"
+)
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
@@ -40,6 +59,11 @@ type NewsListsResponse struct {
 	News    []models.NewsWithCategories
 }
 
+type NewsResponse struct {
+	Success bool
+	News    models.NewsWithCategories
+}
+
 func (h *NewsHandler) CreateNews(c *fiber.Ctx) error {
 	var reqForm models.NewsCreateForm
 	if err := c.BodyParser(&reqForm); err != nil {
@@ -51,7 +75,7 @@ func (h *NewsHandler) CreateNews(c *fiber.Ctx) error {
 		return apperrors.NewValidation(err.Error())
 	}
 
-	id, err := h.service.CreateNews(reqForm)
+	id, err := h.service.CreateNews(c.UserContext(), reqForm)
 	if err != nil {
 		return err
 	}
@@ -79,7 +103,7 @@ func (h *NewsHandler) EditNews(c *fiber.Ctx) error {
 		return apperrors.NewValidation(err.Error())
 	}
 
-	if err = h.service.EditNews(id, editForm); err != nil {
+	if err = h.service.EditNews(c.UserContext(), id, editForm); err != nil {
 		return err
 	}
 
@@ -103,10 +127,127 @@ func (h *NewsHandler) ListNews(c *fiber.Ctx) error {
 		return err
 	}
 
-	newsList, err := h.service.ListNews(limit, offset)
+	renderHTML, err := parseRender(c)
 	if err != nil {
 		return err
 	}
 
+	filter, err := parseNewsFilter(c)
+	if err != nil {
+		return err
+	}
+
+	newsList, err := h.service.ListNews(c.UserContext(), limit, offset, filter)
+	if err != nil {
+		return err
+	}
+
+	if err = h.localize(c, newsList); err != nil {
+		return err
+	}
+
+	// Last-Modified списка - время самого свежего изменения на странице. Удаление новости или сдвиг
+	// страницы его не меняют, их ловит только ETag тела, поэтому If-None-Match проверяется первым.
+	var lastModified time.Time
+	for _, n := range newsList {
+		if n.UpdatedAt.After(lastModified) {
+			lastModified = n.UpdatedAt
+		}
+	}
+	setLastModified(c, lastModified)
+
+	if renderHTML {
+		for i := range newsList {
+			useHTML(&newsList[i])
+		}
+	}
+
 	return c.Status(fiber.StatusOK).JSON(NewsListsResponse{Success: true, News: newsList})
 }
+
+func (h *NewsHandler) GetNews(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return apperrors.NewBadRequest("Invalid ID format")
+	}
+
+	renderHTML, err := parseRender(c)
+	if err != nil {
+		return err
+	}
+
+	news, err := h.service.GetNews(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	if err = h.localizeOne(c, &news); err != nil {
+		return err
+	}
+
+	setLastModified(c, news.UpdatedAt)
+	if renderHTML {
+		useHTML(&news)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(NewsResponse{Success: true, News: news})
+}
+
+// parseRender разбирает ?render=: raw (по умолчанию) отдаёт текст в исходном формате, html - санитизированный HTML
+func parseRender(c *fiber.Ctx) (bool, error) {
+	switch c.Query("render", RenderRaw) {
+	case RenderRaw:
+		return false, nil
+	case RenderHTML:
+		return true, nil
+	default:
+		return false, apperrors.NewBadRequest("render must be html or raw")
+	}
+}
+
+// useHTML подменяет Content готовым HTML, ContentFormat описывает то, что лежит в Content
+func useHTML(news *models.NewsWithCategories) {
+	news.Content = news.ContentHTML
+	news.ContentFormat = content.FormatHTML
+}
+
+// GetNewsBySlug отдаёт новость по текущему slug, а по прежнему перенаправляет на текущий с 301
+func (h *NewsHandler) GetNewsBySlug(c *fiber.Ctx) error {
+	renderHTML, err := parseRender(c)
+	if err != nil {
+		return err
+	}
+
+	slug := c.Params("slug")
+	news, err := h.service.GetNewsBySlug(c.UserContext(), slug)
+	if err != nil {
+		return err
+	}
+
+	if news.Slug != slug {
+		location := "/news/by-slug/" + url.PathEscape(news.Slug)
+		if query := c.Context().QueryArgs().String(); query != "" {
+			location += "?" + query
+		}
+		return c.Redirect(location, fiber.StatusMovedPermanently)
+	}
+
+	if err = h.localizeOne(c, &news); err != nil {
+		return err
+	}
+
+	setLastModified(c, news.UpdatedAt)
+	if renderHTML {
+		useHTML(&news)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(NewsResponse{Success: true, News: news})
+}
+
+func setLastModified(c *fiber.Ctx, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	c.Set(fiber.HeaderLastModified, t.UTC().Format(http.TimeFormat))
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"service/internal/apperrors"
+	"service/internal/models"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TagsResponse struct {
+	Success bool
+	Tags    []models.TagCount
+}
+
+// ListTags подсказывает теги по началу ?prefix=, сначала самые используемые
+func (h *NewsHandler) ListTags(c *fiber.Ctx) error {
+	limit, err := strconv.ParseInt(c.Query("limit", "10"), 10, 64)
+	if err != nil {
+		return apperrors.NewBadRequest("limit must be a valid number")
+	}
+	if err = ValidatePaginationParams(limit, 0); err != nil {
+		return err
+	}
+
+	// Префикс нормализуется как тег, чтобы "Выборы 20" находил "выборы-2026"
+	prefix := models.NormalizeTag(c.Query("prefix"))
+	if utf8.RuneCountInString(prefix) > models.MaxTagLength {
+		return apperrors.NewBadRequest(fmt.Sprintf("prefix length must be less or equal to %d", models.MaxTagLength))
+	}
+
+	tags, err := h.service.ListTags(c.UserContext(), prefix, limit)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(TagsResponse{Success: true, Tags: tags})
+}
+
+// parseNewsFilter разбирает фильтр списка: ?tag=a&tag=b - новости с обоими тегами
+func parseNewsFilter(c *fiber.Ctx) (models.NewsFilter, error) {
+	var filter models.NewsFilter
+	for _, raw := range c.Context().QueryArgs().PeekMulti("tag") {
+		tag := models.NormalizeTag(string(raw))
+		if err := models.ValidateTag(tag); err != nil {
+			return models.NewsFilter{}, apperrors.NewBadRequest(err.Error())
+		}
+		if !slices.Contains(filter.Tags, tag) {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	if len(filter.Tags) > models.MaxTags {
+		return models.NewsFilter{}, apperrors.NewBadRequest(models.ErrTagsLength.Error())
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"service/internal/apperrors"
+	"service/internal/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/text/language"
+)
+
+// maxLocaleChain ограничивает цепочку языков, которую клиент может задать через Accept-Language
+const maxLocaleChain = 10
+
+// CreateTranslation добавляет перевод новости на язык :locale
+func (h *NewsHandler) CreateTranslation(c *fiber.Ctx) error {
+	id, locale, err := h.translationParams(c)
+	if err != nil {
+		return err
+	}
+
+	var reqForm models.NewsTranslationCreateForm
+	if err = c.BodyParser(&reqForm); err != nil {
+		return apperrors.NewBadRequest("Invalid request body")
+	}
+
+	reqForm.Normalize()
+	if err = reqForm.Validate(); err != nil {
+		return apperrors.NewValidation(err.Error())
+	}
+
+	if err = h.service.CreateTranslation(c.UserContext(), id, locale, reqForm); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(SuccessResponse{
+		Success: true,
+	})
+}
+
+// EditTranslation меняет перевод новости на язык :locale
+func (h *NewsHandler) EditTranslation(c *fiber.Ctx) error {
+	id, locale, err := h.translationParams(c)
+	if err != nil {
+		return err
+	}
+
+	var editForm models.NewsTranslationEditForm
+	if err = c.BodyParser(&editForm); err != nil {
+		return apperrors.NewBadRequest("Invalid request body")
+	}
+
+	editForm.Normalize()
+	if err = editForm.Validate(); err != nil {
+		return apperrors.NewValidation(err.Error())
+	}
+
+	if err = h.service.EditTranslation(c.UserContext(), id, locale, editForm); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(SuccessResponse{
+		Success: true,
+	})
+}
+
+// translationParams разбирает :id и :locale. Текст на языке по умолчанию - сама новость, а не перевод.
+func (h *NewsHandler) translationParams(c *fiber.Ctx) (int64, string, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, "", apperrors.NewBadRequest("Invalid ID format")
+	}
+
+	locale, err := models.ParseLocale(c.Params("locale"))
+	if err != nil {
+		return 0, "", apperrors.NewBadRequest(err.Error())
+	}
+	if locale == h.defaultLocale {
+		return 0, "", apperrors.NewValidation("locale must differ from the default locale, edit the news itself instead")
+	}
+
+	return id, locale, nil
+}
+
+// localize переводит новости на язык клиента. ?lang= приоритетнее Accept-Language,
+// без подходящего перевода новость остаётся на языке по умолчанию.
+func (h *NewsHandler) localize(c *fiber.Ctx, newsList []models.NewsWithCategories) error {
+	c.Vary(fiber.HeaderAcceptLanguage)
+
+	locales, err := h.localeChain(c)
+	if err != nil {
+		return err
+	}
+
+	for i := range newsList {
+		newsList[i].Locale = h.defaultLocale
+	}
+
+	return h.service.LocalizeNews(c.UserContext(), newsList, locales)
+}
+
+// localizeOne переводит одну новость и сообщает её язык в Content-Language
+func (h *NewsHandler) localizeOne(c *fiber.Ctx, news *models.NewsWithCategories) error {
+	newsList := []models.NewsWithCategories{*news}
+	if err := h.localize(c, newsList); err != nil {
+		return err
+	}
+
+	*news = newsList[0]
+	c.Set(fiber.HeaderContentLanguage, news.Locale)
+	return nil
+}
+
+// localeChain возвращает языки для поиска перевода в порядке предпочтения: каждый запрошенный язык,
+// за ним его родительские ("de-AT" -> "de"). Цепочка обрывается на языке по умолчанию - дальше искать незачем.
+func (h *NewsHandler) localeChain(c *fiber.Ctx) ([]string, error) {
+	var tags []language.Tag
+	if lang := c.Query("lang"); lang != "" {
+		locale, err := models.ParseLocale(lang)
+		if err != nil {
+			return nil, apperrors.NewBadRequest("lang must be a valid BCP 47 language tag")
+		}
+		tags = []language.Tag{language.Make(locale)}
+	} else {
+		// Некорректный Accept-Language не повод отказывать в чтении, отдаём язык по умолчанию
+		tags, _, _ = language.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+	}
+
+	var chain []string
+	seen := make(map[string]struct{})
+	for _, tag := range tags {
+		for ; tag != language.Und; tag = tag.Parent() {
+			locale := tag.String()
+			if locale == h.defaultLocale {
+				return chain, nil
+			}
+			if _, ok := seen[locale]; ok {
+				continue
+			}
+			if len(chain) == maxLocaleChain {
+				return chain, nil
+			}
+			seen[locale] = struct{}{}
+			chain = append(chain, locale)
+		}
+	}
+
+	return chain, nil
+}
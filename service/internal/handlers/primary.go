@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"service/pkg/db"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	readPrimaryCookie = "read_primary"
+	readPrimaryHeader = "X-Read-Primary"
+)
+
+// ReadPrimaryAfterWrite отправляет чтения клиента в основную базу в течение window после его успешной записи,
+// чтобы он сразу видел свои изменения, даже если реплики отстают.
+// Запись помечается cookie, клиенты без cookie могут передать заголовок X-Read-Primary.
+func ReadPrimaryAfterWrite(window time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Cookies(readPrimaryCookie) != "" || c.Get(readPrimaryHeader) != "" {
+			c.SetUserContext(db.WithPrimary(c.UserContext()))
+		}
+
+		err := c.Next()
+
+		if err == nil && c.Method() != fiber.MethodGet && c.Response().StatusCode() < http.StatusBadRequest {
+			c.Cookie(&fiber.Cookie{
+				Name:     readPrimaryCookie,
+				Value:    "1",
+				Path:     "/",
+				MaxAge:   int(window.Seconds()),
+				HTTPOnly: true,
+				SameSite: fiber.CookieSameSiteLaxMode,
+			})
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"math"
+	"service/internal/apperrors"
+	"service/pkg/ratelimit"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimit ограничивает частоту запросов клиента в группе роутов.
+// Клиент определяется функцией clientID (см. ClientID).
+// При недоступности хранилища запрос пропускается, чтобы не ронять сервис вместе с ним.
+func RateLimit(store ratelimit.Store, clientID func(c *fiber.Ctx) string, group string, limit ratelimit.Limit, log *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := store.Take(c.UserContext(), group+":"+clientID(c), limit)
+		if err != nil {
+			log.WithContext(c.UserContext()).WithError(err).WithField("group", group).Error("Rate limit store failed")
+			return c.Next()
+		}
+
+		c.Set(headerRateLimitLimit, strconv.Itoa(limit.Burst))
+		c.Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Set(headerRateLimitReset, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			return apperrors.NewTooManyRequests("Too many requests")
+		}
+
+		return c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"service/pkg/requestid"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID берёт id запроса из X-Request-ID или генерирует новый,
+// кладёт его в UserContext и возвращает клиенту в том же заголовке
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(requestid.Header, id)
+		c.SetUserContext(requestid.NewContext(c.UserContext(), id))
+
+		return c.Next()
+	}
+}
+
+// validRequestID пропускает только непустые id разумной длины из печатных ASCII символов,
+// чтобы клиент не мог протащить в логи что угодно
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -2,20 +2,60 @@ package handlers
 
 import (
 	handler "service/internal/handlers/news"
+	"service/internal/health"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// RouteMiddlewares - дополнительные middleware для групп роутов на чтение и на запись.
+// Служебные роуты /admin регистрируются, только если задан Admin (проверка доступа).
+// /metrics доступен всегда, Metrics может закрыть его отдельной проверкой.
+// Upload - middleware записи для загрузки файлов, без идемпотентности: хэшировать тело размером
+// с файл ради повтора незачем, повторная загрузка просто создаёт ещё одно вложение.
+type RouteMiddlewares struct {
+	Read    []fiber.Handler
+	Write   []fiber.Handler
+	Upload  []fiber.Handler
+	Admin   []fiber.Handler
+	Metrics []fiber.Handler
+}
+
 // SetupRoutes настраивает все роуты приложения
-func SetupRoutes(app *fiber.App, newsHandler handler.NewsHandler) {
+func SetupRoutes(app *fiber.App, newsHandler handler.NewsHandler, checker *health.Checker, mw RouteMiddlewares) {
 	// API группа с авторизацией
 	//api := app.Group("/api", AuthMiddleware(authToken, log))
 	api := app.Group("/")
 
+	// Служебные роуты без лимитов
+	api.Get("healthz", Liveness())
+	api.Get("readyz", Readiness(checker))
+	api.Get("metrics", chain(mw.Metrics, MetricsHandler())...)
+
 	// Роуты для работы с новостями
-	api.Post("edit/:id", newsHandler.EditNews)
-	api.Get("list", newsHandler.ListNews)
-	api.Post("create", newsHandler.CreateNews)
+	api.Post("edit/:id", chain(mw.Write, newsHandler.EditNews)...)
+	api.Get("list", chain(mw.Read, ConditionalGet(), newsHandler.ListNews)...)
+	api.Get("news/:id", chain(mw.Read, ConditionalGet(), newsHandler.GetNews)...)
+	api.Get("news/by-slug/:slug", chain(mw.Read, ConditionalGet(), newsHandler.GetNewsBySlug)...)
+	api.Get("tags", chain(mw.Read, ConditionalGet(), newsHandler.ListTags)...)
+	api.Post("create", chain(mw.Write, newsHandler.CreateNews)...)
+	api.Post("news/batch", chain(mw.Write, newsHandler.BatchNews)...)
+	api.Post("news/:id/translations/:locale", chain(mw.Write, newsHandler.CreateTranslation)...)
+	api.Patch("news/:id/translations/:locale", chain(mw.Write, newsHandler.EditTranslation)...)
+	api.Post("news/:id/media", chain(mw.Upload, newsHandler.UploadMedia)...)
+	// Условные запросы и Range обрабатывает сам обработчик, ConditionalGet здесь не нужен
+	api.Get("media/:id", chain(mw.Read, newsHandler.GetMedia)...)
+
+	// Выгрузка и загрузка новостей
+	if mw.Admin != nil {
+		api.Get("admin/export", chain(mw.Admin, newsHandler.ExportNews)...)
+		api.Post("admin/import", chain(mw.Admin, newsHandler.ImportNews)...)
+	}
+}
+
+func chain(middlewares []fiber.Handler, handlers ...fiber.Handler) []fiber.Handler {
+	result := make([]fiber.Handler, 0, len(middlewares)+len(handlers))
+	result = append(result, middlewares...)
+	return append(result, handlers...)
 }
 
 //import (
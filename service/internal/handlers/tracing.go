@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"service/pkg/requestid"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("service/internal/handlers")
+
+// Tracing открывает span на каждый запрос, продолжая трассу из заголовка traceparent,
+// и кладёт контекст со span'ом в UserContext, откуда его берут сервис и репозиторий
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), fiberCarrier{c: c})
+
+		ctx, span := tracer.Start(ctx, c.Method()+" "+c.Path(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Method()),
+				attribute.String("url.path", c.Path()),
+				attribute.String("request.id", requestid.FromContext(c.UserContext())),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(attribute.String("http.route", route))
+
+		status := responseStatus(c, err)
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, strconv.Itoa(status))
+		}
+
+		return err
+	}
+}
+
+// fiberCarrier - propagation.TextMapCarrier поверх заголовков запроса fiber
+type fiberCarrier struct {
+	c *fiber.Ctx
+}
+
+var _ propagation.TextMapCarrier = fiberCarrier{}
+
+func (f fiberCarrier) Get(key string) string {
+	return f.c.Get(key)
+}
+
+func (f fiberCarrier) Set(key, value string) {
+	f.c.Request().Header.Set(key, value)
+}
+
+func (f fiberCarrier) Keys() []string {
+	keys := make([]string, 0)
+	f.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check - проверка одной зависимости, ошибка означает, что зависимость недоступна
+type Check func(ctx context.Context) error
+
+// CheckResult - результат одной проверки
+type CheckResult struct {
+	Status    string
+	LatencyMs float64
+	Error     string `json:",omitempty"`
+}
+
+// Report - сводный отчёт о готовности сервиса
+type Report struct {
+	Status string
+	Checks map[string]CheckResult
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker запускает проверки зависимостей для readiness.
+// После Shutdown сервис всегда считается неготовым, чтобы балансировщик перестал слать трафик.
+type Checker struct {
+	timeout  time.Duration
+	checks   []namedCheck
+	stopping atomic.Bool
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Shutdown переводит сервис в состояние "не готов"
+func (c *Checker) Shutdown() {
+	c.stopping.Store(true)
+}
+
+// Run параллельно выполняет все проверки, каждая ограничена таймаутом
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(c.checks)+1),
+	}
+
+	if c.stopping.Load() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = CheckResult{Status: StatusFail, Error: "service is shutting down"}
+		return report
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := nc.check(checkCtx)
+			result := CheckResult{
+				Status:    StatusOK,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[nc.name] = result
+			if err != nil {
+				report.Status = StatusFail
+			}
+		}(nc)
+	}
+	wg.Wait()
+
+	return report
+}
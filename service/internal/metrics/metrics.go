@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "news"
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Количество HTTP запросов по роуту и статусу",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Длительность обработки HTTP запросов",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RepositoryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_operation_duration_seconds",
+		Help:      "Длительность операций репозитория",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"op"})
+
+	NewsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "created_total",
+		Help:      "Количество созданных новостей",
+	})
+
+	NewsEdited = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "edited_total",
+		Help:      "Количество отредактированных новостей",
+	})
+
+	NewsDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deleted_total",
+		Help:      "Количество удалённых новостей",
+	})
+)
+
+// ObserveRepository записывает длительность операции репозитория, вызывается через defer:
+//
+//	defer metrics.ObserveRepository(op, time.Now())
+func ObserveRepository(op string, start time.Time) {
+	RepositoryDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+}
+
+// RegisterDB экспортирует статистику пула соединений sql.DB
+func RegisterDB(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
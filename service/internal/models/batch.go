@@ -0,0 +1,39 @@
+package models
+
+import "encoding/json"
+
+const (
+	BatchOpCreate = "create"
+	BatchOpEdit   = "edit"
+)
+
+// NewsBatchForm - тело запроса пакетного создания и редактирования
+type NewsBatchForm struct {
+	Operations []NewsBatchOperation `json:"operations"`
+}
+
+// NewsBatchOperation - одна операция пакета, Data разбирается в форму по Op
+type NewsBatchOperation struct {
+	Op   string          `json:"op"`
+	ID   int64           `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+// NewsBatchItem - разобранная и провалидированная операция пакета
+type NewsBatchItem struct {
+	Index  int
+	Op     string
+	NewsID int64
+	Create *NewsCreateForm
+	Edit   *NewsEditForm
+}
+
+// NewsBatchResult - результат выполнения одной операции пакета
+type NewsBatchResult struct {
+	Index   int
+	Op      string
+	Id      int64
+	Status  int
+	Success bool
+	Error   string `json:",omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// IdempotencyRecord - сохранённый ответ на запрос с заголовком Idempotency-Key
+type IdempotencyRecord struct {
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+}
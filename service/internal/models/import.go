@@ -0,0 +1,56 @@
+package models
+
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+)
+
+// NewsImportItem - провалидированная строка импорта.
+// Новость с тем же ExternalID обновляется, без ExternalID всегда создаётся новая.
+type NewsImportItem struct {
+	Line       int
+	ExternalID string
+	Form       NewsCreateForm
+}
+
+// NewsImportResult - результат импорта одной строки
+type NewsImportResult struct {
+	Line       int
+	ExternalID string `json:",omitempty"`
+	Id         int64  `json:",omitempty"`
+	Action     string `json:",omitempty"`
+	Success    bool
+	Error      string `json:",omitempty"`
+}
+
+// NewsImportReport - итог импорта: счётчики и ошибки по строкам
+type NewsImportReport struct {
+	Success bool
+	DryRun  bool
+	Total   int
+	Created int
+	Updated int
+	Failed  int
+	Errors  []NewsImportResult
+}
+
+func NewNewsImportReport(dryRun bool) *NewsImportReport {
+	return &NewsImportReport{Success: true, DryRun: dryRun, Errors: []NewsImportResult{}}
+}
+
+func (r *NewsImportReport) Add(result NewsImportResult) {
+	r.Total++
+	if !result.Success {
+		r.Success = false
+		r.Failed++
+		r.Errors = append(r.Errors, result)
+		return
+	}
+
+	switch result.Action {
+	case ImportActionCreate:
+		r.Created++
+	case ImportActionUpdate:
+		r.Updated++
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxAltTextLength - длина колонки media.alt_text в символах
+const MaxAltTextLength = 255
+
+// MediaTypes - типы файлов, которые можно прикрепить к новости. Тип определяется по содержимому файла,
+// а не по заголовку клиента. SVG не принимается: в нём может быть скрипт.
+var MediaTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// Media - файл, прикреплённый к новости. Сам файл лежит в хранилище под StorageKey,
+// клиенту файл отдаётся по адресу /media/{ID}.
+type Media struct {
+	ID       int64
+	NewsID   int64
+	MimeType string
+	Size     int64
+	// Checksum - SHA-256 содержимого в hex, он же ETag при отдаче файла
+	Checksum   string
+	AltText    string
+	CreatedAt  time.Time
+	StorageKey string `json:"-"`
+}
+
+// MediaUpload - загружаемый файл и его описание
+type MediaUpload struct {
+	AltText string
+	Body    io.Reader
+}
+
+func (u *MediaUpload) Normalize() {
+	u.AltText = strings.TrimSpace(u.AltText)
+}
+
+func (u *MediaUpload) Validate() error {
+	if utf8.RuneCountInString(u.AltText) > MaxAltTextLength {
+		return ErrAltTextLength
+	}
+	return nil
+}
+
+// IsMediaType сообщает, можно ли прикрепить файл типа mimeType
+func IsMediaType(mimeType string) bool {
+	return slices.Contains(MediaTypes, mimeType)
+}
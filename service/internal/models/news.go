@@ -1,27 +1,106 @@
 package models
 
+import (
+	"service/internal/content"
+	"time"
+)
+
 //go:generate reform
 //reform:news
 type News struct {
-	ID      int64  `reform:"id,pk"`
-	Title   string `reform:"title"`
-	Content string `reform:"content"`
+	ID        int64     `reform:"id,pk"`
+	Title     string    `reform:"title"`
+	Content   string    `reform:"content"`
+	Version   int64     `reform:"version"`
+	UpdatedAt time.Time `reform:"updated_at"`
+	// ExternalID - id новости во внешней системе, по нему импорт обновляет уже загруженные новости
+	ExternalID *string `reform:"external_id"`
+	// ContentFormat - формат Content: plain, markdown или html
+	ContentFormat string `reform:"content_format"`
+	// ContentHTML - санитизированный HTML из Content, в ответе отдаётся вместо Content при ?render=html
+	ContentHTML string `reform:"content_html" json:"-"`
+	// Slug - текущая часть URL из заголовка, прежние slug новости хранятся в news_slugs
+	Slug string `reform:"slug"`
+}
+
+// RenderContent пересобирает ContentHTML из Content, вызывается при каждом изменении текста или формата.
+// Новость без формата, как и в базе по умолчанию, считается простым текстом.
+func (n *News) RenderContent() {
+	if n.ContentFormat == "" {
+		n.ContentFormat = content.FormatPlain
+	}
+	n.ContentHTML = content.Render(n.ContentFormat, n.Content)
 }
 
-// NewsWithCategories используется для ответа
+// NewsWithCategories используется для ответа.
+// Теги, как и категории, идут по возрастанию, вложения - в порядке загрузки.
+// Locale - язык заголовка и текста, заполняется при выборе перевода на чтении.
 type NewsWithCategories struct {
 	News
 	Categories []int64
+	Tags       []string
+	Media      []Media
+	Locale     string `json:",omitempty"`
 }
 
 type NewsEditForm struct {
-	Title      *string  `json:"title" validate:"omitempty"`
-	Content    *string  `json:"content" validate:"omitempty"`
-	Categories *[]int64 `json:"categories" validate:"omitempty" `
+	Title            *string  `json:"title" validate:"omitempty"`
+	Content          *string  `json:"content" validate:"omitempty"`
+	ContentFormat    *string  `json:"content_format" validate:"omitempty"`
+	Categories       *[]int64 `json:"categories" validate:"omitempty" `
+	AddCategories    *[]int64 `json:"add_categories" validate:"omitempty"`
+	RemoveCategories *[]int64 `json:"remove_categories" validate:"omitempty"`
+	// Tags заменяет теги новости целиком
+	Tags *[]string `json:"tags" validate:"omitempty"`
+}
+
+// CategoryChanges - изменение категорий новости: полная замена (Replace) либо добавление и удаление
+type CategoryChanges struct {
+	Replace *[]int64
+	Add     []int64
+	Remove  []int64
+}
+
+func (c CategoryChanges) IsEmpty() bool {
+	return c.Replace == nil && len(c.Add) == 0 && len(c.Remove) == 0
 }
 
 type NewsCreateForm struct {
-	Title      string   `json:"title" validate:"omitempty"`
-	Content    string   `json:"content" validate:"omitempty"`
-	Categories *[]int64 `json:"categories" validate:"omitempty" `
+	Title         string   `json:"title" validate:"omitempty"`
+	Content       string   `json:"content" validate:"omitempty"`
+	ContentFormat string   `json:"content_format" validate:"omitempty"`
+	Categories    *[]int64 `json:"categories" validate:"omitempty" `
+	Tags          []string `json:"tags" validate:"omitempty"`
+}
+
+// UpdateFields возвращает изменяемые поля новости в виде, который ожидает репозиторий
+func (n *NewsEditForm) UpdateFields() map[string]interface{} {
+	updateFields := make(map[string]interface{})
+	if n.Title != nil {
+		updateFields["title"] = n.Title
+	}
+	if n.Content != nil {
+		updateFields["content"] = n.Content
+	}
+	if n.ContentFormat != nil {
+		updateFields["content_format"] = n.ContentFormat
+	}
+	if n.Tags != nil {
+		updateFields["tags"] = n.Tags
+	}
+
+	return updateFields
+}
+
+// CategoryChanges возвращает изменения категорий из формы редактирования
+func (n *NewsEditForm) CategoryChanges() CategoryChanges {
+	changes := CategoryChanges{Replace: n.Categories}
+	if n.AddCategories != nil {
+		changes.Add = *n.AddCategories
+	}
+	if n.RemoveCategories != nil {
+		changes.Remove = *n.RemoveCategories
+	}
+
+	return changes
 }
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTagLength - длина колонки tags.name в символах
+	MaxTagLength = 64
+	// MaxTags - сколько тегов может быть у новости и в фильтре списка
+	MaxTags = 20
+)
+
+// TagCount - тег и число новостей с ним, для подсказок при вводе
+type TagCount struct {
+	Name  string
+	Count int64
+}
+
+// NewsFilter - условия выборки списка новостей. Tags - новость должна иметь все перечисленные теги.
+type NewsFilter struct {
+	Tags []string
+}
+
+// NormalizeTag приводит тег к виду, в котором он хранится: нижний регистр, без пробелов по краям,
+// пробелы внутри заменены одним дефисом - "Выборы  2026" и "выборы-2026" один тег
+func NormalizeTag(raw string) string {
+	return strings.Join(strings.Fields(strings.ToLower(raw)), "-")
+}
+
+// ValidateTag проверяет нормализованный тег. Запятая запрещена: в CSV и newsctl теги перечисляются через неё.
+func ValidateTag(tag string) error {
+	if utf8.RuneCountInString(tag) < 1 || utf8.RuneCountInString(tag) > MaxTagLength {
+		return ErrTagLength
+	}
+	if strings.ContainsRune(tag, ',') {
+		return ErrTagComma
+	}
+	return nil
+}
+
+// normalizeTags нормализует теги и убирает повторы, сохраняя порядок первого появления
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, raw := range tags {
+		tag := NormalizeTag(raw)
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func validateTags(tags []string) error {
+	if len(tags) > MaxTags {
+		return ErrTagsLength
+	}
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// maxLocaleLength - длина колонки locale, RFC 5646 советует поддерживать теги хотя бы такой длины
+const maxLocaleLength = 35
+
+var ErrLocale = errors.New("locale must be a valid BCP 47 language tag")
+
+// NewsTranslation - заголовок и текст новости на языке Locale.
+// Основной текст новости написан на языке по умолчанию и переводом не считается.
+type NewsTranslation struct {
+	NewsID        int64
+	Locale        string
+	Title         string
+	Content       string
+	ContentFormat string
+	ContentHTML   string
+	UpdatedAt     time.Time
+}
+
+// RenderContent пересобирает ContentHTML перевода по тем же правилам, что и у новости
+func (t *NewsTranslation) RenderContent() {
+	n := News{Content: t.Content, ContentFormat: t.ContentFormat}
+	n.RenderContent()
+	t.ContentFormat, t.ContentHTML = n.ContentFormat, n.ContentHTML
+}
+
+// Translate подменяет заголовок и текст новости переводом
+func (n *NewsWithCategories) Translate(t NewsTranslation) {
+	n.Title = t.Title
+	n.Content = t.Content
+	n.ContentFormat = t.ContentFormat
+	n.ContentHTML = t.ContentHTML
+	n.Locale = t.Locale
+}
+
+type NewsTranslationCreateForm struct {
+	Title         string `json:"title" validate:"omitempty"`
+	Content       string `json:"content" validate:"omitempty"`
+	ContentFormat string `json:"content_format" validate:"omitempty"`
+}
+
+type NewsTranslationEditForm struct {
+	Title         *string `json:"title" validate:"omitempty"`
+	Content       *string `json:"content" validate:"omitempty"`
+	ContentFormat *string `json:"content_format" validate:"omitempty"`
+}
+
+func (f *NewsTranslationCreateForm) Validate() error {
+	return validateText(f.Title, f.Content, f.ContentFormat)
+}
+
+func (f *NewsTranslationCreateForm) Normalize() {
+	normalizeText(&f.Title, &f.Content, &f.ContentFormat)
+}
+
+func (f *NewsTranslationEditForm) Validate() error {
+	if f.Title == nil && f.Content == nil && f.ContentFormat == nil {
+		return ErrBodyEmpty
+	}
+	return validateTextChanges(f.Title, f.Content, f.ContentFormat)
+}
+
+func (f *NewsTranslationEditForm) Normalize() {
+	normalizeTextChanges(&f.Title, &f.Content, &f.ContentFormat)
+}
+
+// UpdateFields возвращает изменяемые поля перевода с теми же ключами, что и у NewsEditForm
+func (f *NewsTranslationEditForm) UpdateFields() map[string]interface{} {
+	updateFields := make(map[string]interface{})
+	if f.Title != nil {
+		updateFields["title"] = f.Title
+	}
+	if f.Content != nil {
+		updateFields["content"] = f.Content
+	}
+	if f.ContentFormat != nil {
+		updateFields["content_format"] = f.ContentFormat
+	}
+	return updateFields
+}
+
+// ParseLocale приводит языковой тег к каноническому виду: "EN_us" -> "en-US"
+func ParseLocale(raw string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(raw))
+	if err != nil || tag == language.Und || len(tag.String()) > maxLocaleLength {
+		return "", ErrLocale
+	}
+	return tag.String(), nil
+}
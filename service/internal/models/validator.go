@@ -2,6 +2,8 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"service/internal/content"
 	"strings"
 	"unicode/utf8"
 )
@@ -10,51 +12,132 @@ var (
 	ErrBodyEmpty        = errors.New("body cannot be empty")
 	ErrTitleLength      = errors.New("title length must be between 1 and 255")
 	ErrContentLength    = errors.New("content length must be greater 1")
+	ErrContentFormat    = fmt.Errorf("content_format must be one of %s", strings.Join(content.Formats, ", "))
 	ErrCategoriesLength = errors.New("categories length must be greater 1")
+	ErrCategoriesMixed  = errors.New("categories cannot be combined with add_categories or remove_categories")
+	ErrCategoriesClash  = errors.New("add_categories and remove_categories must not intersect")
+	ErrTagLength        = fmt.Errorf("tag length must be between 1 and %d", MaxTagLength)
+	ErrTagComma         = errors.New("tag cannot contain commas")
+	ErrTagsLength       = fmt.Errorf("tags length must be less or equal to %d", MaxTags)
+	ErrAltTextLength    = fmt.Errorf("alt length must be less or equal to %d", MaxAltTextLength)
+	ErrMediaType        = fmt.Errorf("file type must be one of %s", strings.Join(MediaTypes, ", "))
 )
 
 func (n *NewsCreateForm) Validate() error {
-	if utf8.RuneCountInString(n.Title) < 1 || utf8.RuneCountInString(n.Title) > 255 {
-		return ErrTitleLength
-	}
-
-	if utf8.RuneCountInString(n.Content) < 1 {
-		return ErrContentLength
+	if err := validateText(n.Title, n.Content, n.ContentFormat); err != nil {
+		return err
 	}
-
-	return nil
+	return validateTags(n.Tags)
 }
 
 func (n *NewsCreateForm) Normalize() {
-	n.Title = strings.TrimSpace(n.Title)
-	n.Content = strings.TrimSpace(n.Content)
+	normalizeText(&n.Title, &n.Content, &n.ContentFormat)
+	n.Tags = normalizeTags(n.Tags)
 }
 
 func (n *NewsEditForm) Validate() error {
-	if n.Title == nil && n.Content == nil && n.Categories == nil {
+	if n.Title == nil && n.Content == nil && n.ContentFormat == nil && n.Categories == nil &&
+		n.AddCategories == nil && n.RemoveCategories == nil && n.Tags == nil {
 		return ErrBodyEmpty
 	}
-	if n.Title != nil && (utf8.RuneCountInString(*n.Title) < 1 || utf8.RuneCountInString(*n.Title) > 255) {
-		return ErrTitleLength
+	if err := validateTextChanges(n.Title, n.Content, n.ContentFormat); err != nil {
+		return err
 	}
-	if n.Content != nil && utf8.RuneCountInString(*n.Content) < 1 {
-		return ErrContentLength
+	// Пустой список тегов допустим: он снимает с новости все теги
+	if n.Tags != nil {
+		if err := validateTags(*n.Tags); err != nil {
+			return err
+		}
 	}
 	if n.Categories != nil && len(*n.Categories) < 1 {
 		return ErrCategoriesLength
 	}
+	if n.Categories != nil && (n.AddCategories != nil || n.RemoveCategories != nil) {
+		return ErrCategoriesMixed
+	}
+	if n.AddCategories != nil && len(*n.AddCategories) < 1 {
+		return ErrCategoriesLength
+	}
+	if n.RemoveCategories != nil && len(*n.RemoveCategories) < 1 {
+		return ErrCategoriesLength
+	}
+	if n.AddCategories != nil && n.RemoveCategories != nil {
+		added := make(map[int64]struct{}, len(*n.AddCategories))
+		for _, id := range *n.AddCategories {
+			added[id] = struct{}{}
+		}
+		for _, id := range *n.RemoveCategories {
+			if _, ok := added[id]; ok {
+				return ErrCategoriesClash
+			}
+		}
+	}
 
 	return nil
 }
 
 func (n *NewsEditForm) Normalize() {
-	if n.Title != nil {
-		trimmed := strings.TrimSpace(*n.Title)
-		n.Title = &trimmed
+	normalizeTextChanges(&n.Title, &n.Content, &n.ContentFormat)
+	if n.Tags != nil {
+		tags := normalizeTags(*n.Tags)
+		n.Tags = &tags
+	}
+}
+
+// validateText - общие правила заголовка, текста и формата новости и её переводов
+func validateText(title, text, format string) error {
+	if utf8.RuneCountInString(title) < 1 || utf8.RuneCountInString(title) > 255 {
+		return ErrTitleLength
+	}
+
+	if utf8.RuneCountInString(text) < 1 {
+		return ErrContentLength
+	}
+
+	if !content.IsValidFormat(format) {
+		return ErrContentFormat
+	}
+
+	return nil
+}
+
+// validateTextChanges проверяет по правилам validateText только переданные поля
+func validateTextChanges(title, text, format *string) error {
+	if title != nil && (utf8.RuneCountInString(*title) < 1 || utf8.RuneCountInString(*title) > 255) {
+		return ErrTitleLength
+	}
+	if text != nil && utf8.RuneCountInString(*text) < 1 {
+		return ErrContentLength
+	}
+	if format != nil && !content.IsValidFormat(*format) {
+		return ErrContentFormat
+	}
+	return nil
+}
+
+// normalizeText обрезает пробелы, формат без учёта регистра, по умолчанию - простой текст
+func normalizeText(title, text, format *string) {
+	*title = strings.TrimSpace(*title)
+	*text = strings.TrimSpace(*text)
+	*format = strings.ToLower(strings.TrimSpace(*format))
+	if *format == "" {
+		*format = content.FormatPlain
+	}
+}
+
+func normalizeTextChanges(title, text, format **string) {
+	if *title != nil {
+		trimmed := strings.TrimSpace(**title)
+		*title = &trimmed
+	}
+
+	if *text != nil {
+		trimmed := strings.TrimSpace(**text)
+		*text = &trimmed
 	}
 
-	if n.Content != nil {
-		trimmed := strings.TrimSpace(*n.Content)
-		n.Content = &trimmed
+	if *format != nil {
+		lowered := strings.ToLower(strings.TrimSpace(**format))
+		*format = &lowered
 	}
 }
@@ -0,0 +1,85 @@
+package newsio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"service/internal/models"
+	"sort"
+)
+
+// importChunkSize - сколько строк импорта передаётся в load за один вызов
+const importChunkSize = 500
+
+// ImportFunc загружает пачку провалидированных строк, как INewsService.ImportNews
+type ImportFunc func(ctx context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error)
+
+// InputError - входные данные нельзя дочитать (битый CSV, ошибка чтения), в отличие от ошибки load
+type InputError struct {
+	Err error
+}
+
+func (e *InputError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// Import читает строки импорта и загружает их пачками через load, не держа весь файл в памяти.
+// Невалидные строки попадают в отчёт, ошибки отчёта отсортированы по номеру строки.
+// Ошибка load возвращается как есть, ошибка чтения входных данных - как *InputError.
+func Import(ctx context.Context, r io.Reader, format string, dryRun bool, load ImportFunc) (*models.NewsImportReport, error) {
+	report := models.NewNewsImportReport(dryRun)
+	items := make([]models.NewsImportItem, 0, importChunkSize)
+
+	var loadErr error
+	flush := func() error {
+		if len(items) == 0 {
+			return nil
+		}
+		results, err := load(ctx, items, dryRun)
+		if err != nil {
+			loadErr = err
+			return err
+		}
+		for _, result := range results {
+			report.Add(result)
+		}
+		items = items[:0]
+		return nil
+	}
+
+	err := Decode(r, format, func(line Line) error {
+		if line.Err != nil {
+			report.Add(models.NewsImportResult{
+				Line:       line.Item.Line,
+				ExternalID: line.Item.ExternalID,
+				Error:      line.Err.Error(),
+			})
+			return nil
+		}
+
+		items = append(items, line.Item)
+		if len(items) == importChunkSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		if loadErr != nil && errors.Is(err, loadErr) {
+			return nil, loadErr
+		}
+		return nil, &InputError{Err: err}
+	}
+
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+
+	return report, nil
+}
@@ -0,0 +1,280 @@
+// Package newsio читает и пишет новости в форматах обмена JSON Lines и CSV.
+// Формат выгрузки можно загрузить обратно: лишние поля (ID, Slug, Version, UpdatedAt) при импорте игнорируются.
+package newsio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"service/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+
+	maxLineSize = 16 * 1024 * 1024
+)
+
+var (
+	ErrUnknownFormat = fmt.Errorf("format must be one of %q, %q", FormatJSONL, FormatCSV)
+	csvHeader        = []string{"id", "external_id", "slug", "title", "content", "content_format", "categories", "tags", "version", "updated_at"}
+)
+
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Encoder пишет новости по одной, не накапливая их в памяти
+type Encoder interface {
+	Encode(news models.NewsWithCategories) error
+	Flush() error
+}
+
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	switch format {
+	case FormatJSONL:
+		bw := bufio.NewWriter(w)
+		return &jsonlEncoder{w: bw, enc: json.NewEncoder(bw)}, nil
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+type jsonlEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(news models.NewsWithCategories) error {
+	return e.enc.Encode(news)
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+type csvEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (e *csvEncoder) Encode(news models.NewsWithCategories) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	externalID := ""
+	if news.ExternalID != nil {
+		externalID = *news.ExternalID
+	}
+
+	return e.w.Write([]string{
+		strconv.FormatInt(news.ID, 10),
+		externalID,
+		news.Slug,
+		news.Title,
+		news.Content,
+		news.ContentFormat,
+		joinIDs(news.Categories),
+		strings.Join(news.Tags, ","),
+		strconv.FormatInt(news.Version, 10),
+		news.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+func (e *csvEncoder) Flush() error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Line - строка импорта: либо провалидированная новость, либо ошибка разбора или валидации
+type Line struct {
+	Item models.NewsImportItem
+	Err  error
+}
+
+// importLine - строка JSON Lines: форма создания плюс внешний id.
+// Выгрузка пишет внешний id и формат как ExternalID и ContentFormat,
+// а форма ждёт external_id и content_format, принимаем оба варианта.
+type importLine struct {
+	ExternalID         string `json:"external_id"`
+	ExportedExternalID string `json:"ExternalID"`
+	ExportedFormat     string `json:"ContentFormat"`
+	models.NewsCreateForm
+}
+
+// Decode читает строки импорта и вызывает fn для каждой, включая невалидные.
+// Ошибка возвращается только если дальше читать нельзя (битый CSV, ошибка чтения, ошибка fn).
+// Номер строки - номер строки в файле, для CSV заголовок считается первой строкой.
+func Decode(r io.Reader, format string, fn func(Line) error) error {
+	switch format {
+	case FormatJSONL:
+		return decodeJSONL(r, fn)
+	case FormatCSV:
+		return decodeCSV(r, fn)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+func decodeJSONL(r io.Reader, fn func(Line) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for number := 1; scanner.Scan(); number++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var line importLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			if err = fn(Line{Item: models.NewsImportItem{Line: number}, Err: errors.New("invalid JSON")}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if line.ExternalID == "" {
+			line.ExternalID = line.ExportedExternalID
+		}
+		if line.ContentFormat == "" {
+			line.ContentFormat = line.ExportedFormat
+		}
+		if err := fn(newLine(number, line.ExternalID, line.NewsCreateForm)); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func decodeCSV(r io.Reader, fn func(Line) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"title", "content"} {
+		if _, ok := columns[required]; !ok {
+			return fmt.Errorf("CSV header must contain %q column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		number, _ := reader.FieldPos(0)
+
+		form := models.NewsCreateForm{
+			Title:         field(record, "title"),
+			Content:       field(record, "content"),
+			ContentFormat: field(record, "content_format"),
+		}
+
+		if raw := strings.TrimSpace(field(record, "categories")); raw != "" {
+			categories, err := parseIDs(raw)
+			if err != nil {
+				if err = fn(Line{Item: models.NewsImportItem{Line: number}, Err: err}); err != nil {
+					return err
+				}
+				continue
+			}
+			form.Categories = &categories
+		}
+
+		// Пустая колонка тегов, как и категорий, оставляет теги новости как есть
+		if raw := strings.TrimSpace(field(record, "tags")); raw != "" {
+			form.Tags = strings.Split(raw, ",")
+		}
+
+		if err = fn(newLine(number, field(record, "external_id"), form)); err != nil {
+			return err
+		}
+	}
+}
+
+func newLine(number int, externalID string, form models.NewsCreateForm) Line {
+	item := models.NewsImportItem{
+		Line:       number,
+		ExternalID: strings.TrimSpace(externalID),
+	}
+
+	form.Normalize()
+	if err := form.Validate(); err != nil {
+		return Line{Item: item, Err: err}
+	}
+	if len(item.ExternalID) > 255 {
+		return Line{Item: item, Err: errors.New("external_id length must be less or equal to 255")}
+	}
+
+	item.Form = form
+	return Line{Item: item}
+}
+
+func joinIDs(ids []int64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseIDs(raw string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid category id %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
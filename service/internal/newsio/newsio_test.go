@@ -0,0 +1,129 @@
+package newsio
+
+import (
+	"bytes"
+	"context"
+	"service/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	externalID := "ext-1"
+	exported := []models.NewsWithCategories{
+		{
+			News: models.News{
+				ID:            7,
+				Title:         "title",
+				Content:       "**content**",
+				ContentFormat: "markdown",
+				ExternalID:    &externalID,
+				Slug:          "title",
+				Version:       3,
+				UpdatedAt:     time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC),
+			},
+			Categories: []int64{1, 2},
+			Tags:       []string{"go", "news"},
+		},
+		{
+			News: models.News{ID: 8, Title: "plain", Content: "text", ContentFormat: "plain"},
+		},
+	}
+
+	for _, format := range []string{FormatJSONL, FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			encoder, err := NewEncoder(&buf, format)
+			require.NoError(t, err)
+			for _, news := range exported {
+				require.NoError(t, encoder.Encode(news))
+			}
+			require.NoError(t, encoder.Flush())
+
+			var lines []Line
+			require.NoError(t, Decode(&buf, format, func(line Line) error {
+				lines = append(lines, line)
+				return nil
+			}))
+			require.Len(t, lines, 2)
+
+			first := lines[0]
+			require.NoError(t, first.Err)
+			assert.Equal(t, "ext-1", first.Item.ExternalID, "exported external id is imported back")
+			assert.Equal(t, "title", first.Item.Form.Title)
+			assert.Equal(t, "**content**", first.Item.Form.Content)
+			assert.Equal(t, "markdown", first.Item.Form.ContentFormat)
+			if assert.NotNil(t, first.Item.Form.Categories) {
+				assert.Equal(t, []int64{1, 2}, *first.Item.Form.Categories)
+			}
+			assert.Equal(t, []string{"go", "news"}, first.Item.Form.Tags)
+
+			second := lines[1]
+			require.NoError(t, second.Err)
+			assert.Empty(t, second.Item.ExternalID)
+			assert.Equal(t, "plain", second.Item.Form.Title)
+		})
+	}
+}
+
+func TestDecodeJSONLFormKeys(t *testing.T) {
+	input := `{"external_id":"ext-2","title":"title","content":"content","content_format":"html"}`
+
+	var lines []Line
+	require.NoError(t, Decode(strings.NewReader(input), FormatJSONL, func(line Line) error {
+		lines = append(lines, line)
+		return nil
+	}))
+	require.Len(t, lines, 1)
+	require.NoError(t, lines[0].Err)
+	assert.Equal(t, "ext-2", lines[0].Item.ExternalID)
+	assert.Equal(t, "html", lines[0].Item.Form.ContentFormat)
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+
+	var input strings.Builder
+	for i := 0; i < importChunkSize+1; i++ {
+		input.WriteString(`{"title":"title","content":"content"}` + "\n")
+	}
+	input.WriteString("{broken\n")
+
+	var chunks []int
+	load := func(_ context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error) {
+		assert.True(t, dryRun)
+		chunks = append(chunks, len(items))
+		results := make([]models.NewsImportResult, 0, len(items))
+		for _, item := range items {
+			results = append(results, models.NewsImportResult{Line: item.Line, Action: models.ImportActionCreate, Success: true})
+		}
+		return results, nil
+	}
+
+	report, err := Import(ctx, strings.NewReader(input.String()), FormatJSONL, true, load)
+	require.NoError(t, err)
+	assert.Equal(t, []int{importChunkSize, 1}, chunks)
+	assert.Equal(t, importChunkSize+2, report.Total)
+	assert.Equal(t, importChunkSize+1, report.Created)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, importChunkSize+2, report.Errors[0].Line)
+
+	t.Run("input error", func(t *testing.T) {
+		_, err := Import(ctx, strings.NewReader("name\nvalue\n"), FormatCSV, false, load)
+		var inputErr *InputError
+		assert.ErrorAs(t, err, &inputErr)
+	})
+
+	t.Run("load error", func(t *testing.T) {
+		failed := assert.AnError
+		_, err := Import(ctx, strings.NewReader(`{"title":"title","content":"content"}`), FormatJSONL, false,
+			func(context.Context, []models.NewsImportItem, bool) ([]models.NewsImportResult, error) {
+				return nil, failed
+			})
+		assert.Same(t, failed, err)
+	})
+}
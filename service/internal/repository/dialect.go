@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"embed"
+	"encoding/json"
+	"service/internal/configs"
+	"strings"
+
+	"github.com/lib/pq"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/sqlite3"
+)
+
+//go:embed sql/postgres/*.sql sql/sqlite/*.sql
+var sqlFiles embed.FS
+
+// dialect - то, чем запросы репозиториев различаются в Postgres и SQLite и что не скрывает reform
+type dialect struct {
+	driver string
+	// array передаёт срез одним параметром запроса: pq.Array в Postgres, JSON массив для json_each в SQLite
+	array func(v interface{}) interface{}
+	// forUpdate блокирует выбранную строку до конца транзакции. В SQLite транзакция
+	// начинается с BEGIN IMMEDIATE и уже держит блокировку записи, отдельная блокировка не нужна.
+	forUpdate string
+}
+
+var (
+	postgresDialect = dialect{
+		driver:    configs.DriverPostgres,
+		array:     func(v interface{}) interface{} { return pq.Array(v) },
+		forUpdate: " FOR UPDATE",
+	}
+	sqliteDialect = dialect{
+		driver: configs.DriverSQLite,
+		array:  jsonArray,
+	}
+)
+
+func dialectOf(db *reform.DB) dialect {
+	if db.Dialect == sqlite3.Dialect {
+		return sqliteDialect
+	}
+	return postgresDialect
+}
+
+// query возвращает SQL файл драйвера. Файлы встроены в бинарник,
+// поэтому отсутствующий файл - ошибка разработки и проявляется при создании репозитория.
+func (d dialect) query(name string) string {
+	query, err := sqlFiles.ReadFile("sql/" + d.driver + "/" + name)
+	if err != nil {
+		panic(err)
+	}
+	return string(query)
+}
+
+func jsonArray(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// Передаются только срезы чисел и строк, они всегда сериализуются
+		panic(err)
+	}
+	return string(data)
+}
+
+// likeEscaper экранирует спецсимволы LIKE, запросы объявляют '\' символом экранирования
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike делает из строки шаблон LIKE, совпадающий только с ней самой
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"service/internal/apperrors"
+	"service/internal/metrics"
+	"service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+)
+
+// idempotencyLease - на сколько запрос занимает ключ. Если экземпляр сервиса упал, не завершив запрос,
+// после аренды ключ перехватит повтор. Запрос дольше аренды может выполниться повторно.
+const idempotencyLease = 5 * time.Minute
+
+// idempotencyQueries - SQL запросы ключей идемпотентности для драйвера базы
+type idempotencyQueries struct {
+	deleteExpiredKey  string
+	deleteExpiredKeys string
+	insertKey         string
+	selectKey         string
+	takeOverKey       string
+	updateKeyResponse string
+	deletePendingKey  string
+}
+
+func newIdempotencyQueries(d dialect) idempotencyQueries {
+	return idempotencyQueries{
+		deleteExpiredKey:  d.query("delete_expired_idempotency_key.sql"),
+		deleteExpiredKeys: d.query("delete_expired_idempotency_keys.sql"),
+		insertKey:         d.query("insert_idempotency_key.sql"),
+		selectKey:         d.query("select_idempotency_key.sql"),
+		takeOverKey:       d.query("take_over_idempotency_key.sql"),
+		updateKeyResponse: d.query("update_idempotency_key_response.sql"),
+		deletePendingKey:  d.query("delete_pending_idempotency_key.sql"),
+	}
+}
+
+//go:generate mockery --name=IIdempotencyRepository --output=mocks --outpkg=mocks --case=snake --with-expecter
+type IIdempotencyRepository interface {
+	// Acquire занимает ключ клиента до вызова Complete или Abort у возвращённой блокировки.
+	// Ключи разных клиентов не пересекаются. Если запрос с тем же ключом ещё выполняется,
+	// возвращается apperrors.NewConflict.
+	Acquire(ctx context.Context, client, key, requestHash string, ttl time.Duration) (IdempotencyLock, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyLock interface {
+	// Stored возвращает ранее сохранённый ответ или nil, если запрос с этим ключом выполняется впервые
+	Stored() *models.IdempotencyRecord
+	// Complete сохраняет ответ и освобождает ключ
+	Complete(record models.IdempotencyRecord) error
+	// Abort освобождает ключ без сохранения ответа, повтор запроса выполнится заново
+	Abort()
+}
+
+// errIdempotencyKeyInProgress - ключ занят запросом, который ещё не завершился
+var errIdempotencyKeyInProgress = apperrors.NewConflict("A request with this Idempotency-Key is still in progress")
+
+// IdempotencyRepository хранит ключи идемпотентности в базе.
+// Ключ занимается строкой без ответа с токеном и сроком аренды. Каждый шаг - отдельный короткий запрос,
+// транзакция и соединение на время обработки запроса не держатся.
+type IdempotencyRepository struct {
+	db      *reform.DB
+	queries idempotencyQueries
+	log     *logrus.Logger
+}
+
+func NewIdempotencyRepository(db *reform.DB, log *logrus.Logger) IIdempotencyRepository {
+	return &IdempotencyRepository{
+		db:      db,
+		queries: newIdempotencyQueries(dialectOf(db)),
+		log:     log,
+	}
+}
+
+func (r *IdempotencyRepository) Acquire(ctx context.Context, client, key, requestHash string, ttl time.Duration) (IdempotencyLock, error) {
+	const op = "repository.idempotency.Acquire"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	log := r.log.WithContext(ctx).WithField("idempotency_key", key)
+
+	if _, err := r.db.ExecContext(ctx, r.queries.deleteExpiredKey, client, key); err != nil {
+		log.WithError(err).Error("Failed to delete expired idempotency key")
+		return nil, fmt.Errorf("%s: failed to delete expired key: %w", op, err)
+	}
+
+	lock := &idempotencyLock{repo: r, client: client, key: key, token: uuid.NewString(), ctx: ctx}
+
+	result, err := r.db.ExecContext(ctx, r.queries.insertKey,
+		client, key, requestHash, lock.token, idempotencyLease.Seconds(), ttl.Seconds())
+	if err != nil {
+		log.WithError(err).Error("Failed to insert idempotency key")
+		return nil, fmt.Errorf("%s: failed to insert key: %w", op, err)
+	}
+	if inserted, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	} else if inserted == 1 {
+		return lock, nil
+	}
+
+	var (
+		record      models.IdempotencyRecord
+		statusCode  sql.NullInt64
+		contentType sql.NullString
+	)
+	err = r.db.QueryRowContext(ctx, r.queries.selectKey, client, key).
+		Scan(&record.RequestHash, &statusCode, &contentType, &record.ResponseBody)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Ключ освободили между вставкой и чтением
+		return nil, errIdempotencyKeyInProgress
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to select idempotency key")
+		return nil, fmt.Errorf("%s: failed to select key: %w", op, err)
+	}
+
+	if statusCode.Valid {
+		record.StatusCode = int(statusCode.Int64)
+		record.ContentType = contentType.String
+		lock.stored = &record
+		return lock, nil
+	}
+
+	// Ответа нет: ключ занят другим запросом или остался от упавшего экземпляра с истёкшей арендой
+	result, err = r.db.ExecContext(ctx, r.queries.takeOverKey,
+		client, key, requestHash, lock.token, idempotencyLease.Seconds())
+	if err != nil {
+		log.WithError(err).Error("Failed to take over idempotency key")
+		return nil, fmt.Errorf("%s: failed to take over key: %w", op, err)
+	}
+	if taken, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	} else if taken == 0 {
+		return nil, errIdempotencyKeyInProgress
+	}
+
+	return lock, nil
+}
+
+func (r *IdempotencyRepository) PurgeExpired(ctx context.Context) (int64, error) {
+	const op = "repository.idempotency.PurgeExpired"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	result, err := r.db.ExecContext(ctx, r.queries.deleteExpiredKeys)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to purge expired idempotency keys")
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+type idempotencyLock struct {
+	repo     *IdempotencyRepository
+	client   string
+	key      string
+	token    string
+	stored   *models.IdempotencyRecord
+	ctx      context.Context
+	released bool
+}
+
+func (l *idempotencyLock) Stored() *models.IdempotencyRecord {
+	return l.stored
+}
+
+func (l *idempotencyLock) Complete(record models.IdempotencyRecord) error {
+	const op = "repository.idempotency.Complete"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	if l.released || l.stored != nil {
+		return nil
+	}
+	l.released = true
+
+	// Ответ уже отправляется клиенту, сохранить его нужно и после отключения клиента
+	ctx := context.WithoutCancel(l.ctx)
+	_, err := l.repo.db.ExecContext(ctx, l.repo.queries.updateKeyResponse,
+		l.client, l.key, l.token, record.StatusCode, record.ContentType, record.ResponseBody)
+	if err != nil {
+		l.repo.log.WithContext(ctx).WithError(err).WithField("idempotency_key", l.key).Error("Failed to save idempotent response")
+		return fmt.Errorf("%s: failed to save response: %w", op, err)
+	}
+
+	return nil
+}
+
+// Abort удаляет строку без ответа, если она всё ещё принадлежит этому запросу
+func (l *idempotencyLock) Abort() {
+	if l.released || l.stored != nil {
+		return
+	}
+	l.released = true
+
+	// Клиент мог уже отключиться, ключ всё равно нужно освободить
+	ctx := context.WithoutCancel(l.ctx)
+	if _, err := l.repo.db.ExecContext(ctx, l.repo.queries.deletePendingKey, l.client, l.key, l.token); err != nil {
+		l.repo.log.WithContext(ctx).WithError(err).WithField("idempotency_key", l.key).Error("Failed to delete pending idempotency key")
+	}
+}
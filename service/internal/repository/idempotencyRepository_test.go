@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"service/internal/apperrors"
+	"service/internal/configs"
+	"service/internal/models"
+	"service/pkg/db"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+)
+
+func TestMemoryIdempotencyRepositoryContract(t *testing.T) {
+	runIdempotencyRepositoryContract(t, func(t *testing.T) IIdempotencyRepository {
+		return NewMemoryIdempotencyRepository(discardLogger())
+	})
+}
+
+func TestSQLiteIdempotencyRepositoryContract(t *testing.T) {
+	log := discardLogger()
+
+	runIdempotencyRepositoryContract(t, func(t *testing.T) IIdempotencyRepository {
+		cnf := configs.Default().Database
+		cnf.Driver = configs.DriverSQLite
+		cnf.DSN = filepath.Join(t.TempDir(), "idempotency.db")
+
+		sqlDB, reformDB, err := db.InitReformDB(context.Background(), cnf, log)
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		return NewIdempotencyRepository(reformDB, log)
+	})
+}
+
+func TestIdempotencyRepositoryContract(t *testing.T) {
+	dsn := os.Getenv(testDatabaseDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set, run make test-postgres", testDatabaseDSNEnv)
+	}
+
+	sqlDB, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	ctx := context.Background()
+	migrator, err := db.NewMigrator(sqlDB, configs.DriverPostgres)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	log := discardLogger()
+	reformDB := reform.NewDB(sqlDB, postgresql.Dialect, nil)
+
+	runIdempotencyRepositoryContract(t, func(t *testing.T) IIdempotencyRepository {
+		_, err := sqlDB.ExecContext(ctx, "TRUNCATE idempotency_keys")
+		require.NoError(t, err)
+
+		return NewIdempotencyRepository(reformDB, log)
+	})
+}
+
+// runIdempotencyRepositoryContract проверяет, что реализации IIdempotencyRepository занимают и хранят ключи одинаково
+func runIdempotencyRepositoryContract(t *testing.T, newRepository func(t *testing.T) IIdempotencyRepository) {
+	ctx := context.Background()
+	record := models.IdempotencyRecord{
+		RequestHash:  "hash",
+		StatusCode:   201,
+		ContentType:  "application/json",
+		ResponseBody: []byte(`{"id":1}`),
+	}
+
+	t.Run("CompleteAndReplay", func(t *testing.T) {
+		repo := newRepository(t)
+
+		lock, err := repo.Acquire(ctx, "client", "key", "hash", time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, lock.Stored())
+		require.NoError(t, lock.Complete(record))
+
+		lock, err = repo.Acquire(ctx, "client", "key", "other", time.Hour)
+		require.NoError(t, err)
+		if assert.NotNil(t, lock.Stored()) {
+			assert.Equal(t, record, *lock.Stored())
+		}
+		lock.Abort()
+
+		// Abort у сохранённого ответа его не удаляет
+		lock, err = repo.Acquire(ctx, "client", "key", "hash", time.Hour)
+		require.NoError(t, err)
+		assert.NotNil(t, lock.Stored())
+	})
+
+	t.Run("InProgress", func(t *testing.T) {
+		repo := newRepository(t)
+
+		lock, err := repo.Acquire(ctx, "client", "key", "hash", time.Hour)
+		require.NoError(t, err)
+
+		_, err = repo.Acquire(ctx, "client", "key", "hash", time.Hour)
+		var appErr *apperrors.AppError
+		if assert.ErrorAs(t, err, &appErr) {
+			assert.Equal(t, 409, appErr.StatusCode)
+		}
+
+		lock.Abort()
+
+		lock, err = repo.Acquire(ctx, "client", "key", "hash", time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, lock.Stored(), "aborted key is executed again")
+	})
+
+	t.Run("ScopedByClient", func(t *testing.T) {
+		repo := newRepository(t)
+
+		lock, err := repo.Acquire(ctx, "first", "key", "hash", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, lock.Complete(record))
+
+		lock, err = repo.Acquire(ctx, "second", "key", "hash", time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, lock.Stored(), "another client's response is not replayed")
+		lock.Abort()
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		repo := newRepository(t)
+
+		lock, err := repo.Acquire(ctx, "client", "expired", "hash", 10*time.Millisecond)
+		require.NoError(t, err)
+		require.NoError(t, lock.Complete(record))
+
+		lock, err = repo.Acquire(ctx, "client", "kept", "hash", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, lock.Complete(record))
+
+		time.Sleep(50 * time.Millisecond)
+
+		deleted, err := repo.PurgeExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		lock, err = repo.Acquire(ctx, "client", "expired", "hash", time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, lock.Stored())
+		lock.Abort()
+	})
+}
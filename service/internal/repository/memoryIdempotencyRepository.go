@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"service/internal/metrics"
+	"service/internal/models"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryIdempotencyRepository хранит ключи идемпотентности в памяти процесса.
+// Ключ занимается так же, как строкой без ответа в базе, но только внутри одного экземпляра сервиса.
+type MemoryIdempotencyRepository struct {
+	mu   sync.Mutex
+	keys map[memoryIdempotencyID]*memoryIdempotencyKey
+	log  *logrus.Logger
+}
+
+type memoryIdempotencyID struct {
+	client string
+	key    string
+}
+
+type memoryIdempotencyKey struct {
+	requestHash string
+	// record - сохранённый ответ, nil пока запрос выполняется
+	record    *models.IdempotencyRecord
+	expiresAt time.Time
+}
+
+func NewMemoryIdempotencyRepository(log *logrus.Logger) IIdempotencyRepository {
+	return &MemoryIdempotencyRepository{
+		keys: make(map[memoryIdempotencyID]*memoryIdempotencyKey),
+		log:  log,
+	}
+}
+
+func (r *MemoryIdempotencyRepository) Acquire(ctx context.Context, client, key, requestHash string, ttl time.Duration) (IdempotencyLock, error) {
+	const op = "repository.idempotency.Acquire"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := memoryIdempotencyID{client: client, key: key}
+	now := time.Now()
+
+	entry, ok := r.keys[id]
+	if ok && now.After(entry.expiresAt) {
+		ok = false
+	}
+
+	lock := &memoryIdempotencyLock{repo: r, id: id}
+
+	if ok {
+		// Запрос в памяти не переживает падение процесса, поэтому ключ без ответа всегда занят
+		if entry.record == nil {
+			return nil, errIdempotencyKeyInProgress
+		}
+		stored := *entry.record
+		lock.stored = &stored
+		return lock, nil
+	}
+
+	entry = &memoryIdempotencyKey{requestHash: requestHash, expiresAt: now.Add(ttl)}
+	r.keys[id] = entry
+	lock.entry = entry
+
+	return lock, nil
+}
+
+func (r *MemoryIdempotencyRepository) PurgeExpired(ctx context.Context) (int64, error) {
+	const op = "repository.idempotency.PurgeExpired"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	now := time.Now()
+	for id, entry := range r.keys {
+		if entry.record != nil && now.After(entry.expiresAt) {
+			delete(r.keys, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+type memoryIdempotencyLock struct {
+	repo *MemoryIdempotencyRepository
+	id   memoryIdempotencyID
+	// entry - занятая этим запросом запись, nil для сохранённого ответа или после освобождения
+	entry  *memoryIdempotencyKey
+	stored *models.IdempotencyRecord
+}
+
+func (l *memoryIdempotencyLock) Stored() *models.IdempotencyRecord {
+	return l.stored
+}
+
+func (l *memoryIdempotencyLock) Complete(record models.IdempotencyRecord) error {
+	if l.entry == nil {
+		return nil
+	}
+
+	l.repo.mu.Lock()
+	defer l.repo.mu.Unlock()
+
+	// Как и в базе, хэш остаётся от запроса, занявшего ключ
+	record.RequestHash = l.entry.requestHash
+	l.entry.record = &record
+	l.entry = nil
+
+	return nil
+}
+
+func (l *memoryIdempotencyLock) Abort() {
+	if l.entry == nil {
+		return
+	}
+
+	l.repo.mu.Lock()
+	defer l.repo.mu.Unlock()
+
+	if l.repo.keys[l.id] == l.entry {
+		delete(l.repo.keys, l.id)
+	}
+	l.entry = nil
+}
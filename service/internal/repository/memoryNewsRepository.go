@@ -0,0 +1,596 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"service/internal/apperrors"
+	"service/internal/metrics"
+	"service/internal/models"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryNewsRepository - потокобезопасная реализация INewsRepository в памяти для тестов и локального запуска.
+// Семантика та же, что у NewsRepository: новости по убыванию id, пустой массив категорий вместо nil,
+// apperrors.NewNotFound для отсутствующих новостей. Категории возвращаются по возрастанию.
+type MemoryNewsRepository struct {
+	mu    sync.RWMutex
+	state memoryNewsState
+	log   *logrus.Logger
+}
+
+type memoryNewsState struct {
+	news         map[int64]models.NewsWithCategories
+	ids          []int64 // по возрастанию
+	externalIDs  map[string]int64
+	slugs        map[string]int64 // текущие и прежние slug
+	translations map[translationKey]models.NewsTranslation
+	media        map[int64]models.Media
+	lastID       int64
+	lastMediaID  int64
+}
+
+type translationKey struct {
+	newsID int64
+	locale string
+}
+
+func NewMemoryNewsRepository(log *logrus.Logger) INewsRepository {
+	return &MemoryNewsRepository{
+		state: memoryNewsState{
+			news:         make(map[int64]models.NewsWithCategories),
+			externalIDs:  make(map[string]int64),
+			slugs:        make(map[string]int64),
+			translations: make(map[translationKey]models.NewsTranslation),
+			media:        make(map[int64]models.Media),
+		},
+		log: log,
+	}
+}
+
+func (r *MemoryNewsRepository) GetNews(ctx context.Context, limit, offset int64, filter models.NewsFilter) ([]models.NewsWithCategories, error) {
+	const op = "repository.news.GetNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	// Postgres не принимает отрицательные LIMIT и OFFSET, ведём себя так же
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("%s: limit and offset must not be negative", op)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var newsList []models.NewsWithCategories
+	var skipped int64
+	for i := len(r.state.ids) - 1; i >= 0 && int64(len(newsList)) < limit; i-- {
+		n := r.state.news[r.state.ids[i]]
+		if !hasAllTags(n.Tags, filter.Tags) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		newsList = append(newsList, cloneNews(n))
+	}
+
+	return newsList, nil
+}
+
+func (r *MemoryNewsRepository) GetNewsByID(ctx context.Context, newsId int64) (models.NewsWithCategories, error) {
+	const op = "repository.news.GetNewsByID"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n, ok := r.state.news[newsId]
+	if !ok {
+		r.log.WithContext(ctx).WithField("news_id", newsId).Warn("News not found")
+		return models.NewsWithCategories{}, apperrors.NewNotFound("News not found")
+	}
+
+	return cloneNews(n), nil
+}
+
+func (r *MemoryNewsRepository) GetNewsBySlug(ctx context.Context, slug string) (models.NewsWithCategories, error) {
+	const op = "repository.news.GetNewsBySlug"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n, ok := r.state.news[r.state.slugs[slug]]
+	if !ok {
+		r.log.WithContext(ctx).WithField("slug", slug).Warn("News not found")
+		return models.NewsWithCategories{}, apperrors.NewNotFound("News not found")
+	}
+
+	return cloneNews(n), nil
+}
+
+func (r *MemoryNewsRepository) CreateNews(ctx context.Context, createForm models.NewsCreateForm) (int64, error) {
+	const op = "repository.news.CreateNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newsID := r.state.create(createForm, "")
+
+	r.log.WithContext(ctx).WithField("news_id", newsID).Info("News created successfully")
+	return newsID, nil
+}
+
+func (r *MemoryNewsRepository) UpdateNews(ctx context.Context, newsId int64, updateFields map[string]interface{}, categories models.CategoryChanges) error {
+	const op = "repository.news.UpdateNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.state.update(newsId, updateFields, categories); err != nil {
+		r.log.WithContext(ctx).WithField("news_id", newsId).Warn("News not found")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.log.WithContext(ctx).WithField("news_id", newsId).Info("News updated successfully")
+	return nil
+}
+
+func (r *MemoryNewsRepository) DeleteNews(ctx context.Context, newsId int64) ([]models.Media, error) {
+	const op = "repository.news.DeleteNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.news[newsId]
+	if !ok {
+		r.log.WithContext(ctx).WithField("news_id", newsId).Warn("News not found")
+		return nil, apperrors.NewNotFound("News not found")
+	}
+
+	delete(r.state.news, newsId)
+	if i, found := slices.BinarySearch(r.state.ids, newsId); found {
+		r.state.ids = slices.Delete(r.state.ids, i, i+1)
+	}
+	if n.ExternalID != nil {
+		delete(r.state.externalIDs, *n.ExternalID)
+	}
+	maps.DeleteFunc(r.state.slugs, func(_ string, id int64) bool {
+		return id == newsId
+	})
+	maps.DeleteFunc(r.state.translations, func(key translationKey, _ models.NewsTranslation) bool {
+		return key.newsID == newsId
+	})
+	for _, m := range n.Media {
+		delete(r.state.media, m.ID)
+	}
+
+	r.log.WithContext(ctx).WithField("news_id", newsId).Info("News deleted successfully")
+	return slices.Clone(n.Media), nil
+}
+
+// ApplyBatch в атомарном режиме работает на копии состояния и откатывает её при первой ошибке
+func (r *MemoryNewsRepository) ApplyBatch(ctx context.Context, items []models.NewsBatchItem, atomic bool) ([]models.NewsBatchResult, error) {
+	const op = "repository.news.ApplyBatch"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var snapshot memoryNewsState
+	if atomic {
+		snapshot = r.state.clone()
+	}
+
+	results := make([]models.NewsBatchResult, 0, len(items))
+	for _, item := range items {
+		result := r.state.applyBatchItem(item)
+		results = append(results, result)
+
+		if atomic && !result.Success {
+			r.log.WithContext(ctx).WithField("index", item.Index).Warn("Batch item failed, rolling back batch")
+			// id не переиспользуются, как и значения последовательности после отката в Postgres
+			snapshot.lastID = r.state.lastID
+			r.state = snapshot
+			return rollbackBatchResults(results, items), nil
+		}
+	}
+
+	return results, nil
+}
+
+func (r *MemoryNewsRepository) ExportNews(ctx context.Context, fn func(models.NewsWithCategories) error) error {
+	const op = "repository.news.ExportNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	// Копируем под блокировкой, а fn вызываем без неё, чтобы медленный клиент не держал запись
+	r.mu.RLock()
+	newsList := make([]models.NewsWithCategories, 0, len(r.state.ids))
+	for _, id := range r.state.ids {
+		newsList = append(newsList, cloneNews(r.state.news[id]))
+	}
+	r.mu.RUnlock()
+
+	for _, n := range newsList {
+		if err := fn(n); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryNewsRepository) ImportNews(ctx context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error) {
+	const op = "repository.news.ImportNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	planned := make(map[string]struct{})
+	results := make([]models.NewsImportResult, 0, len(items))
+	for _, item := range items {
+		result := models.NewsImportResult{
+			Line:       item.Line,
+			ExternalID: item.ExternalID,
+			Action:     models.ImportActionCreate,
+			Success:    true,
+		}
+
+		existingID, exists := r.state.externalIDs[item.ExternalID]
+		if item.ExternalID == "" {
+			exists = false
+		}
+
+		if dryRun {
+			if _, ok := planned[item.ExternalID]; ok || exists {
+				result.Action = models.ImportActionUpdate
+			}
+			if item.ExternalID != "" {
+				planned[item.ExternalID] = struct{}{}
+			}
+			results = append(results, result)
+			continue
+		}
+
+		if exists {
+			updateFields := map[string]interface{}{
+				"title":          &item.Form.Title,
+				"content":        &item.Form.Content,
+				"content_format": &item.Form.ContentFormat,
+			}
+			if item.Form.Tags != nil {
+				updateFields["tags"] = &item.Form.Tags
+			}
+			_ = r.state.update(existingID, updateFields, models.CategoryChanges{Replace: item.Form.Categories})
+			result.Id = existingID
+			result.Action = models.ImportActionUpdate
+		} else {
+			result.Id = r.state.create(item.Form, item.ExternalID)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *MemoryNewsRepository) CreateTranslation(ctx context.Context, newsId int64, locale string, createForm models.NewsTranslationCreateForm) error {
+	const op = "repository.news.CreateTranslation"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.news[newsId]; !ok {
+		r.log.WithContext(ctx).WithField("news_id", newsId).Warn("News not found")
+		return apperrors.NewNotFound("News not found")
+	}
+
+	key := translationKey{newsID: newsId, locale: locale}
+	if _, ok := r.state.translations[key]; ok {
+		r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "locale": locale}).Warn("Translation already exists")
+		return apperrors.NewConflict("Translation already exists")
+	}
+
+	translation := models.NewsTranslation{
+		NewsID:        newsId,
+		Locale:        locale,
+		Title:         createForm.Title,
+		Content:       createForm.Content,
+		ContentFormat: createForm.ContentFormat,
+		UpdatedAt:     time.Now().UTC(),
+	}
+	translation.RenderContent()
+	r.state.translations[key] = translation
+	r.state.touch(newsId, translation.UpdatedAt)
+
+	r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "locale": locale}).Info("Translation created successfully")
+	return nil
+}
+
+func (r *MemoryNewsRepository) UpdateTranslation(ctx context.Context, newsId int64, locale string, updateFields map[string]interface{}) error {
+	const op = "repository.news.UpdateTranslation"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.news[newsId]; !ok {
+		r.log.WithContext(ctx).WithField("news_id", newsId).Warn("News not found")
+		return apperrors.NewNotFound("News not found")
+	}
+
+	key := translationKey{newsID: newsId, locale: locale}
+	translation, ok := r.state.translations[key]
+	if !ok {
+		r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "locale": locale}).Warn("Translation not found")
+		return apperrors.NewNotFound("Translation not found")
+	}
+
+	applyTranslationFields(&translation, updateFields)
+	translation.UpdatedAt = time.Now().UTC()
+	r.state.translations[key] = translation
+	r.state.touch(newsId, translation.UpdatedAt)
+
+	r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "locale": locale}).Info("Translation updated successfully")
+	return nil
+}
+
+func (r *MemoryNewsRepository) GetTranslations(ctx context.Context, newsIds []int64, locales []string) ([]models.NewsTranslation, error) {
+	const op = "repository.news.GetTranslations"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	translations := []models.NewsTranslation{}
+	for _, newsId := range newsIds {
+		for _, locale := range locales {
+			if t, ok := r.state.translations[translationKey{newsID: newsId, locale: locale}]; ok {
+				translations = append(translations, t)
+			}
+		}
+	}
+
+	return translations, nil
+}
+
+func (r *MemoryNewsRepository) GetTags(ctx context.Context, prefix string, limit int64) ([]models.TagCount, error) {
+	const op = "repository.news.GetTags"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.RLock()
+	counts := make(map[string]int64)
+	for _, n := range r.state.news {
+		for _, tag := range n.Tags {
+			if strings.HasPrefix(tag, prefix) {
+				counts[tag]++
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	tags := make([]models.TagCount, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, models.TagCount{Name: name, Count: count})
+	}
+	// Порядок как в NewsRepository.GetTags: сначала самые используемые, при равенстве по имени
+	slices.SortFunc(tags, func(a, b models.TagCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	if int64(len(tags)) > limit {
+		tags = tags[:limit]
+	}
+
+	return tags, nil
+}
+
+func (r *MemoryNewsRepository) CreateMedia(ctx context.Context, media *models.Media) error {
+	const op = "repository.news.CreateMedia"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.news[media.NewsID]
+	if !ok {
+		r.log.WithContext(ctx).WithField("news_id", media.NewsID).Warn("News not found")
+		return apperrors.NewNotFound("News not found")
+	}
+
+	r.state.lastMediaID++
+	media.ID = r.state.lastMediaID
+	media.CreatedAt = time.Now().UTC()
+	r.state.media[media.ID] = *media
+
+	n.Media = append(slices.Clone(n.Media), *media)
+	r.state.news[media.NewsID] = n
+	r.state.touch(media.NewsID, media.CreatedAt)
+
+	r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": media.NewsID, "media_id": media.ID}).Info("Media created successfully")
+	return nil
+}
+
+func (r *MemoryNewsRepository) GetMedia(ctx context.Context, mediaId int64) (models.Media, error) {
+	const op = "repository.news.GetMedia"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	media, ok := r.state.media[mediaId]
+	if !ok {
+		r.log.WithContext(ctx).WithField("media_id", mediaId).Warn("Media not found")
+		return models.Media{}, apperrors.NewNotFound("Media not found")
+	}
+
+	return media, nil
+}
+
+func (s *memoryNewsState) create(createForm models.NewsCreateForm, externalID string) int64 {
+	s.lastID++
+	n := models.NewsWithCategories{
+		News: models.News{
+			ID:            s.lastID,
+			Title:         createForm.Title,
+			Content:       createForm.Content,
+			ContentFormat: createForm.ContentFormat,
+			Version:       1,
+			UpdatedAt:     time.Now().UTC(),
+		},
+		Categories: []int64{},
+		Tags:       sortTags(createForm.Tags),
+		Media:      []models.Media{},
+	}
+	n.RenderContent()
+	if createForm.Categories != nil {
+		n.Categories = normalizeCategories(*createForm.Categories)
+	}
+	if externalID != "" {
+		n.ExternalID = &externalID
+		s.externalIDs[externalID] = n.ID
+	}
+	n.Slug = s.assignSlug(n.ID, n.Title)
+
+	s.news[n.ID] = n
+	s.ids = append(s.ids, n.ID)
+
+	return n.ID
+}
+
+func (s *memoryNewsState) update(newsId int64, updateFields map[string]interface{}, categories models.CategoryChanges) error {
+	n, ok := s.news[newsId]
+	if !ok {
+		return apperrors.NewNotFound("News not found")
+	}
+
+	if title, ok := updateFields["title"]; ok {
+		n.Title = *title.(*string)
+		if n.Slug != slugBase(n.Title) {
+			n.Slug = s.assignSlug(newsId, n.Title)
+		}
+	}
+	content, contentChanged := updateFields["content"]
+	if contentChanged {
+		n.Content = *content.(*string)
+	}
+	format, formatChanged := updateFields["content_format"]
+	if formatChanged {
+		n.ContentFormat = *format.(*string)
+	}
+	if contentChanged || formatChanged {
+		n.RenderContent()
+	}
+	if tags, ok := updateFields["tags"]; ok {
+		n.Tags = sortTags(*tags.(*[]string))
+	}
+
+	// Порядок как в NewsRepository.updateCategories: замена, удаление, добавление
+	if categories.Replace != nil {
+		n.Categories = normalizeCategories(*categories.Replace)
+	}
+	if len(categories.Remove) > 0 {
+		n.Categories = slices.DeleteFunc(slices.Clone(n.Categories), func(id int64) bool {
+			return slices.Contains(categories.Remove, id)
+		})
+	}
+	if len(categories.Add) > 0 {
+		n.Categories = normalizeCategories(append(slices.Clone(n.Categories), categories.Add...))
+	}
+
+	n.Version++
+	n.UpdatedAt = time.Now().UTC()
+	s.news[newsId] = n
+
+	return nil
+}
+
+// touch поднимает версию новости после изменения её перевода, как NewsRepository.touchNews
+func (s *memoryNewsState) touch(newsId int64, updatedAt time.Time) {
+	n := s.news[newsId]
+	n.Version++
+	n.UpdatedAt = updatedAt
+	s.news[newsId] = n
+}
+
+// assignSlug закрепляет за новостью slug из заголовка, как NewsRepository.assignSlug
+func (s *memoryNewsState) assignSlug(newsId int64, title string) string {
+	candidate, _ := slugCandidate(slugBase(title), s.slugs, newsId)
+	s.slugs[candidate] = newsId
+	return candidate
+}
+
+func (s *memoryNewsState) applyBatchItem(item models.NewsBatchItem) models.NewsBatchResult {
+	switch item.Op {
+	case models.BatchOpCreate:
+		newsID := s.create(*item.Create, "")
+		return models.NewsBatchResult{Index: item.Index, Op: item.Op, Id: newsID, Status: 201, Success: true}
+	case models.BatchOpEdit:
+		if err := s.update(item.NewsID, item.Edit.UpdateFields(), item.Edit.CategoryChanges()); err != nil {
+			return batchErrorResult(item, err)
+		}
+		return models.NewsBatchResult{Index: item.Index, Op: item.Op, Id: item.NewsID, Status: 200, Success: true}
+	default:
+		return batchErrorResult(item, apperrors.NewValidation("unknown operation"))
+	}
+}
+
+// clone копирует карты и срез id, сами новости не меняются на месте и копирования не требуют
+func (s *memoryNewsState) clone() memoryNewsState {
+	return memoryNewsState{
+		news:         maps.Clone(s.news),
+		ids:          slices.Clone(s.ids),
+		externalIDs:  maps.Clone(s.externalIDs),
+		slugs:        maps.Clone(s.slugs),
+		translations: maps.Clone(s.translations),
+		media:        maps.Clone(s.media),
+		lastID:       s.lastID,
+		lastMediaID:  s.lastMediaID,
+	}
+}
+
+func cloneNews(n models.NewsWithCategories) models.NewsWithCategories {
+	n.Categories = slices.Clone(n.Categories)
+	n.Tags = slices.Clone(n.Tags)
+	n.Media = slices.Clone(n.Media)
+	if n.ExternalID != nil {
+		externalID := *n.ExternalID
+		n.ExternalID = &externalID
+	}
+	return n
+}
+
+// normalizeCategories убирает повторы и сортирует категории, всегда возвращает новый срез
+func normalizeCategories(categories []int64) []int64 {
+	normalized := append([]int64{}, categories...)
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// sortTags возвращает новый отсортированный срез тегов, теги уже нормализованы формой
+func sortTags(tags []string) []string {
+	sorted := append([]string{}, tags...)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
+
+func hasAllTags(tags, wanted []string) bool {
+	for _, tag := range wanted {
+		if !slices.Contains(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
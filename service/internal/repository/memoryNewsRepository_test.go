@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMemoryNewsRepositoryContract(t *testing.T) {
+	runNewsRepositoryContract(t, func(t *testing.T) INewsRepository {
+		return NewMemoryNewsRepository(discardLogger())
+	})
+}
+
+func discardLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	repository "service/internal/repository"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IIdempotencyRepository is an autogenerated mock type for the IIdempotencyRepository type
+type IIdempotencyRepository struct {
+	mock.Mock
+}
+
+type IIdempotencyRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *IIdempotencyRepository) EXPECT() *IIdempotencyRepository_Expecter {
+	return &IIdempotencyRepository_Expecter{mock: &_m.Mock}
+}
+
+// Acquire provides a mock function with given fields: ctx, client, key, requestHash, ttl
+func (_m *IIdempotencyRepository) Acquire(ctx context.Context, client string, key string, requestHash string, ttl time.Duration) (repository.IdempotencyLock, error) {
+	ret := _m.Called(ctx, client, key, requestHash, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Acquire")
+	}
+
+	var r0 repository.IdempotencyLock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration) (repository.IdempotencyLock, error)); ok {
+		return rf(ctx, client, key, requestHash, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration) repository.IdempotencyLock); ok {
+		r0 = rf(ctx, client, key, requestHash, ttl)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.IdempotencyLock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, client, key, requestHash, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IIdempotencyRepository_Acquire_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Acquire'
+type IIdempotencyRepository_Acquire_Call struct {
+	*mock.Call
+}
+
+// Acquire is a helper method to define mock.On call
+//   - ctx context.Context
+//   - client string
+//   - key string
+//   - requestHash string
+//   - ttl time.Duration
+func (_e *IIdempotencyRepository_Expecter) Acquire(ctx interface{}, client interface{}, key interface{}, requestHash interface{}, ttl interface{}) *IIdempotencyRepository_Acquire_Call {
+	return &IIdempotencyRepository_Acquire_Call{Call: _e.mock.On("Acquire", ctx, client, key, requestHash, ttl)}
+}
+
+func (_c *IIdempotencyRepository_Acquire_Call) Run(run func(ctx context.Context, client string, key string, requestHash string, ttl time.Duration)) *IIdempotencyRepository_Acquire_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *IIdempotencyRepository_Acquire_Call) Return(_a0 repository.IdempotencyLock, _a1 error) *IIdempotencyRepository_Acquire_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *IIdempotencyRepository_Acquire_Call) RunAndReturn(run func(context.Context, string, string, string, time.Duration) (repository.IdempotencyLock, error)) *IIdempotencyRepository_Acquire_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeExpired provides a mock function with given fields: ctx
+func (_m *IIdempotencyRepository) PurgeExpired(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeExpired")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IIdempotencyRepository_PurgeExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeExpired'
+type IIdempotencyRepository_PurgeExpired_Call struct {
+	*mock.Call
+}
+
+// PurgeExpired is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *IIdempotencyRepository_Expecter) PurgeExpired(ctx interface{}) *IIdempotencyRepository_PurgeExpired_Call {
+	return &IIdempotencyRepository_PurgeExpired_Call{Call: _e.mock.On("PurgeExpired", ctx)}
+}
+
+func (_c *IIdempotencyRepository_PurgeExpired_Call) Run(run func(ctx context.Context)) *IIdempotencyRepository_PurgeExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *IIdempotencyRepository_PurgeExpired_Call) Return(_a0 int64, _a1 error) *IIdempotencyRepository_PurgeExpired_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *IIdempotencyRepository_PurgeExpired_Call) RunAndReturn(run func(context.Context) (int64, error)) *IIdempotencyRepository_PurgeExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewIIdempotencyRepository creates a new instance of IIdempotencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIIdempotencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IIdempotencyRepository {
+	mock := &IIdempotencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	models "service/internal/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -21,9 +22,385 @@ func (_m *INewsRepository) EXPECT() *INewsRepository_Expecter {
 	return &INewsRepository_Expecter{mock: &_m.Mock}
 }
 
-// GetNews provides a mock function with given fields: limit, offset
-func (_m *INewsRepository) GetNews(limit uint64, offset uint64) ([]models.NewsWithCategories, error) {
-	ret := _m.Called(limit, offset)
+// ApplyBatch provides a mock function with given fields: ctx, items, atomic
+func (_m *INewsRepository) ApplyBatch(ctx context.Context, items []models.NewsBatchItem, atomic bool) ([]models.NewsBatchResult, error) {
+	ret := _m.Called(ctx, items, atomic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyBatch")
+	}
+
+	var r0 []models.NewsBatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.NewsBatchItem, bool) ([]models.NewsBatchResult, error)); ok {
+		return rf(ctx, items, atomic)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.NewsBatchItem, bool) []models.NewsBatchResult); ok {
+		r0 = rf(ctx, items, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.NewsBatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.NewsBatchItem, bool) error); ok {
+		r1 = rf(ctx, items, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_ApplyBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyBatch'
+type INewsRepository_ApplyBatch_Call struct {
+	*mock.Call
+}
+
+// ApplyBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []models.NewsBatchItem
+//   - atomic bool
+func (_e *INewsRepository_Expecter) ApplyBatch(ctx interface{}, items interface{}, atomic interface{}) *INewsRepository_ApplyBatch_Call {
+	return &INewsRepository_ApplyBatch_Call{Call: _e.mock.On("ApplyBatch", ctx, items, atomic)}
+}
+
+func (_c *INewsRepository_ApplyBatch_Call) Run(run func(ctx context.Context, items []models.NewsBatchItem, atomic bool)) *INewsRepository_ApplyBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.NewsBatchItem), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_ApplyBatch_Call) Return(_a0 []models.NewsBatchResult, _a1 error) *INewsRepository_ApplyBatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_ApplyBatch_Call) RunAndReturn(run func(context.Context, []models.NewsBatchItem, bool) ([]models.NewsBatchResult, error)) *INewsRepository_ApplyBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateMedia provides a mock function with given fields: ctx, media
+func (_m *INewsRepository) CreateMedia(ctx context.Context, media *models.Media) error {
+	ret := _m.Called(ctx, media)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMedia")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Media) error); ok {
+		r0 = rf(ctx, media)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// INewsRepository_CreateMedia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMedia'
+type INewsRepository_CreateMedia_Call struct {
+	*mock.Call
+}
+
+// CreateMedia is a helper method to define mock.On call
+//   - ctx context.Context
+//   - media *models.Media
+func (_e *INewsRepository_Expecter) CreateMedia(ctx interface{}, media interface{}) *INewsRepository_CreateMedia_Call {
+	return &INewsRepository_CreateMedia_Call{Call: _e.mock.On("CreateMedia", ctx, media)}
+}
+
+func (_c *INewsRepository_CreateMedia_Call) Run(run func(ctx context.Context, media *models.Media)) *INewsRepository_CreateMedia_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Media))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_CreateMedia_Call) Return(_a0 error) *INewsRepository_CreateMedia_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *INewsRepository_CreateMedia_Call) RunAndReturn(run func(context.Context, *models.Media) error) *INewsRepository_CreateMedia_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateNews provides a mock function with given fields: ctx, createForm
+func (_m *INewsRepository) CreateNews(ctx context.Context, createForm models.NewsCreateForm) (int64, error) {
+	ret := _m.Called(ctx, createForm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNews")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewsCreateForm) (int64, error)); ok {
+		return rf(ctx, createForm)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.NewsCreateForm) int64); ok {
+		r0 = rf(ctx, createForm)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.NewsCreateForm) error); ok {
+		r1 = rf(ctx, createForm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_CreateNews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNews'
+type INewsRepository_CreateNews_Call struct {
+	*mock.Call
+}
+
+// CreateNews is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createForm models.NewsCreateForm
+func (_e *INewsRepository_Expecter) CreateNews(ctx interface{}, createForm interface{}) *INewsRepository_CreateNews_Call {
+	return &INewsRepository_CreateNews_Call{Call: _e.mock.On("CreateNews", ctx, createForm)}
+}
+
+func (_c *INewsRepository_CreateNews_Call) Run(run func(ctx context.Context, createForm models.NewsCreateForm)) *INewsRepository_CreateNews_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.NewsCreateForm))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_CreateNews_Call) Return(_a0 int64, _a1 error) *INewsRepository_CreateNews_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_CreateNews_Call) RunAndReturn(run func(context.Context, models.NewsCreateForm) (int64, error)) *INewsRepository_CreateNews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTranslation provides a mock function with given fields: ctx, newsId, locale, createForm
+func (_m *INewsRepository) CreateTranslation(ctx context.Context, newsId int64, locale string, createForm models.NewsTranslationCreateForm) error {
+	ret := _m.Called(ctx, newsId, locale, createForm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTranslation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.NewsTranslationCreateForm) error); ok {
+		r0 = rf(ctx, newsId, locale, createForm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// INewsRepository_CreateTranslation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTranslation'
+type INewsRepository_CreateTranslation_Call struct {
+	*mock.Call
+}
+
+// CreateTranslation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - newsId int64
+//   - locale string
+//   - createForm models.NewsTranslationCreateForm
+func (_e *INewsRepository_Expecter) CreateTranslation(ctx interface{}, newsId interface{}, locale interface{}, createForm interface{}) *INewsRepository_CreateTranslation_Call {
+	return &INewsRepository_CreateTranslation_Call{Call: _e.mock.On("CreateTranslation", ctx, newsId, locale, createForm)}
+}
+
+func (_c *INewsRepository_CreateTranslation_Call) Run(run func(ctx context.Context, newsId int64, locale string, createForm models.NewsTranslationCreateForm)) *INewsRepository_CreateTranslation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(models.NewsTranslationCreateForm))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_CreateTranslation_Call) Return(_a0 error) *INewsRepository_CreateTranslation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *INewsRepository_CreateTranslation_Call) RunAndReturn(run func(context.Context, int64, string, models.NewsTranslationCreateForm) error) *INewsRepository_CreateTranslation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteNews provides a mock function with given fields: ctx, newsId
+func (_m *INewsRepository) DeleteNews(ctx context.Context, newsId int64) ([]models.Media, error) {
+	ret := _m.Called(ctx, newsId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteNews")
+	}
+
+	var r0 []models.Media
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Media, error)); ok {
+		return rf(ctx, newsId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Media); ok {
+		r0 = rf(ctx, newsId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Media)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, newsId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_DeleteNews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteNews'
+type INewsRepository_DeleteNews_Call struct {
+	*mock.Call
+}
+
+// DeleteNews is a helper method to define mock.On call
+//   - ctx context.Context
+//   - newsId int64
+func (_e *INewsRepository_Expecter) DeleteNews(ctx interface{}, newsId interface{}) *INewsRepository_DeleteNews_Call {
+	return &INewsRepository_DeleteNews_Call{Call: _e.mock.On("DeleteNews", ctx, newsId)}
+}
+
+func (_c *INewsRepository_DeleteNews_Call) Run(run func(ctx context.Context, newsId int64)) *INewsRepository_DeleteNews_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_DeleteNews_Call) Return(_a0 []models.Media, _a1 error) *INewsRepository_DeleteNews_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_DeleteNews_Call) RunAndReturn(run func(context.Context, int64) ([]models.Media, error)) *INewsRepository_DeleteNews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportNews provides a mock function with given fields: ctx, fn
+func (_m *INewsRepository) ExportNews(ctx context.Context, fn func(models.NewsWithCategories) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportNews")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(models.NewsWithCategories) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// INewsRepository_ExportNews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportNews'
+type INewsRepository_ExportNews_Call struct {
+	*mock.Call
+}
+
+// ExportNews is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(models.NewsWithCategories) error
+func (_e *INewsRepository_Expecter) ExportNews(ctx interface{}, fn interface{}) *INewsRepository_ExportNews_Call {
+	return &INewsRepository_ExportNews_Call{Call: _e.mock.On("ExportNews", ctx, fn)}
+}
+
+func (_c *INewsRepository_ExportNews_Call) Run(run func(ctx context.Context, fn func(models.NewsWithCategories) error)) *INewsRepository_ExportNews_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(models.NewsWithCategories) error))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_ExportNews_Call) Return(_a0 error) *INewsRepository_ExportNews_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *INewsRepository_ExportNews_Call) RunAndReturn(run func(context.Context, func(models.NewsWithCategories) error) error) *INewsRepository_ExportNews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMedia provides a mock function with given fields: ctx, mediaId
+func (_m *INewsRepository) GetMedia(ctx context.Context, mediaId int64) (models.Media, error) {
+	ret := _m.Called(ctx, mediaId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMedia")
+	}
+
+	var r0 models.Media
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Media, error)); ok {
+		return rf(ctx, mediaId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Media); ok {
+		r0 = rf(ctx, mediaId)
+	} else {
+		r0 = ret.Get(0).(models.Media)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, mediaId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_GetMedia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMedia'
+type INewsRepository_GetMedia_Call struct {
+	*mock.Call
+}
+
+// GetMedia is a helper method to define mock.On call
+//   - ctx context.Context
+//   - mediaId int64
+func (_e *INewsRepository_Expecter) GetMedia(ctx interface{}, mediaId interface{}) *INewsRepository_GetMedia_Call {
+	return &INewsRepository_GetMedia_Call{Call: _e.mock.On("GetMedia", ctx, mediaId)}
+}
+
+func (_c *INewsRepository_GetMedia_Call) Run(run func(ctx context.Context, mediaId int64)) *INewsRepository_GetMedia_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_GetMedia_Call) Return(_a0 models.Media, _a1 error) *INewsRepository_GetMedia_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_GetMedia_Call) RunAndReturn(run func(context.Context, int64) (models.Media, error)) *INewsRepository_GetMedia_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNews provides a mock function with given fields: ctx, limit, offset, filter
+func (_m *INewsRepository) GetNews(ctx context.Context, limit int64, offset int64, filter models.NewsFilter) ([]models.NewsWithCategories, error) {
+	ret := _m.Called(ctx, limit, offset, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetNews")
@@ -31,19 +408,19 @@ func (_m *INewsRepository) GetNews(limit uint64, offset uint64) ([]models.NewsWi
 
 	var r0 []models.NewsWithCategories
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64, uint64) ([]models.NewsWithCategories, error)); ok {
-		return rf(limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.NewsFilter) ([]models.NewsWithCategories, error)); ok {
+		return rf(ctx, limit, offset, filter)
 	}
-	if rf, ok := ret.Get(0).(func(uint64, uint64) []models.NewsWithCategories); ok {
-		r0 = rf(limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.NewsFilter) []models.NewsWithCategories); ok {
+		r0 = rf(ctx, limit, offset, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.NewsWithCategories)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint64, uint64) error); ok {
-		r1 = rf(limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, models.NewsFilter) error); ok {
+		r1 = rf(ctx, limit, offset, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -57,15 +434,17 @@ type INewsRepository_GetNews_Call struct {
 }
 
 // GetNews is a helper method to define mock.On call
-//   - limit uint64
-//   - offset uint64
-func (_e *INewsRepository_Expecter) GetNews(limit interface{}, offset interface{}) *INewsRepository_GetNews_Call {
-	return &INewsRepository_GetNews_Call{Call: _e.mock.On("GetNews", limit, offset)}
+//   - ctx context.Context
+//   - limit int64
+//   - offset int64
+//   - filter models.NewsFilter
+func (_e *INewsRepository_Expecter) GetNews(ctx interface{}, limit interface{}, offset interface{}, filter interface{}) *INewsRepository_GetNews_Call {
+	return &INewsRepository_GetNews_Call{Call: _e.mock.On("GetNews", ctx, limit, offset, filter)}
 }
 
-func (_c *INewsRepository_GetNews_Call) Run(run func(limit uint64, offset uint64)) *INewsRepository_GetNews_Call {
+func (_c *INewsRepository_GetNews_Call) Run(run func(ctx context.Context, limit int64, offset int64, filter models.NewsFilter)) *INewsRepository_GetNews_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint64), args[1].(uint64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(models.NewsFilter))
 	})
 	return _c
 }
@@ -75,22 +454,316 @@ func (_c *INewsRepository_GetNews_Call) Return(_a0 []models.NewsWithCategories,
 	return _c
 }
 
-func (_c *INewsRepository_GetNews_Call) RunAndReturn(run func(uint64, uint64) ([]models.NewsWithCategories, error)) *INewsRepository_GetNews_Call {
+func (_c *INewsRepository_GetNews_Call) RunAndReturn(run func(context.Context, int64, int64, models.NewsFilter) ([]models.NewsWithCategories, error)) *INewsRepository_GetNews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNewsByID provides a mock function with given fields: ctx, newsId
+func (_m *INewsRepository) GetNewsByID(ctx context.Context, newsId int64) (models.NewsWithCategories, error) {
+	ret := _m.Called(ctx, newsId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNewsByID")
+	}
+
+	var r0 models.NewsWithCategories
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.NewsWithCategories, error)); ok {
+		return rf(ctx, newsId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.NewsWithCategories); ok {
+		r0 = rf(ctx, newsId)
+	} else {
+		r0 = ret.Get(0).(models.NewsWithCategories)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, newsId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_GetNewsByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNewsByID'
+type INewsRepository_GetNewsByID_Call struct {
+	*mock.Call
+}
+
+// GetNewsByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - newsId int64
+func (_e *INewsRepository_Expecter) GetNewsByID(ctx interface{}, newsId interface{}) *INewsRepository_GetNewsByID_Call {
+	return &INewsRepository_GetNewsByID_Call{Call: _e.mock.On("GetNewsByID", ctx, newsId)}
+}
+
+func (_c *INewsRepository_GetNewsByID_Call) Run(run func(ctx context.Context, newsId int64)) *INewsRepository_GetNewsByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_GetNewsByID_Call) Return(_a0 models.NewsWithCategories, _a1 error) *INewsRepository_GetNewsByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_GetNewsByID_Call) RunAndReturn(run func(context.Context, int64) (models.NewsWithCategories, error)) *INewsRepository_GetNewsByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNewsBySlug provides a mock function with given fields: ctx, slug
+func (_m *INewsRepository) GetNewsBySlug(ctx context.Context, slug string) (models.NewsWithCategories, error) {
+	ret := _m.Called(ctx, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNewsBySlug")
+	}
+
+	var r0 models.NewsWithCategories
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.NewsWithCategories, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.NewsWithCategories); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(models.NewsWithCategories)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_GetNewsBySlug_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNewsBySlug'
+type INewsRepository_GetNewsBySlug_Call struct {
+	*mock.Call
+}
+
+// GetNewsBySlug is a helper method to define mock.On call
+//   - ctx context.Context
+//   - slug string
+func (_e *INewsRepository_Expecter) GetNewsBySlug(ctx interface{}, slug interface{}) *INewsRepository_GetNewsBySlug_Call {
+	return &INewsRepository_GetNewsBySlug_Call{Call: _e.mock.On("GetNewsBySlug", ctx, slug)}
+}
+
+func (_c *INewsRepository_GetNewsBySlug_Call) Run(run func(ctx context.Context, slug string)) *INewsRepository_GetNewsBySlug_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_GetNewsBySlug_Call) Return(_a0 models.NewsWithCategories, _a1 error) *INewsRepository_GetNewsBySlug_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_GetNewsBySlug_Call) RunAndReturn(run func(context.Context, string) (models.NewsWithCategories, error)) *INewsRepository_GetNewsBySlug_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTags provides a mock function with given fields: ctx, prefix, limit
+func (_m *INewsRepository) GetTags(ctx context.Context, prefix string, limit int64) ([]models.TagCount, error) {
+	ret := _m.Called(ctx, prefix, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTags")
+	}
+
+	var r0 []models.TagCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]models.TagCount, error)); ok {
+		return rf(ctx, prefix, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []models.TagCount); ok {
+		r0 = rf(ctx, prefix, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TagCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, prefix, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_GetTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTags'
+type INewsRepository_GetTags_Call struct {
+	*mock.Call
+}
+
+// GetTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+//   - limit int64
+func (_e *INewsRepository_Expecter) GetTags(ctx interface{}, prefix interface{}, limit interface{}) *INewsRepository_GetTags_Call {
+	return &INewsRepository_GetTags_Call{Call: _e.mock.On("GetTags", ctx, prefix, limit)}
+}
+
+func (_c *INewsRepository_GetTags_Call) Run(run func(ctx context.Context, prefix string, limit int64)) *INewsRepository_GetTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_GetTags_Call) Return(_a0 []models.TagCount, _a1 error) *INewsRepository_GetTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_GetTags_Call) RunAndReturn(run func(context.Context, string, int64) ([]models.TagCount, error)) *INewsRepository_GetTags_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateNews provides a mock function with given fields: newsId, updateFields, categories
-func (_m *INewsRepository) UpdateNews(newsId uint64, updateFields map[string]interface{}, categories *[]uint64) error {
-	ret := _m.Called(newsId, updateFields, categories)
+// GetTranslations provides a mock function with given fields: ctx, newsIds, locales
+func (_m *INewsRepository) GetTranslations(ctx context.Context, newsIds []int64, locales []string) ([]models.NewsTranslation, error) {
+	ret := _m.Called(ctx, newsIds, locales)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTranslations")
+	}
+
+	var r0 []models.NewsTranslation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, []string) ([]models.NewsTranslation, error)); ok {
+		return rf(ctx, newsIds, locales)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, []string) []models.NewsTranslation); ok {
+		r0 = rf(ctx, newsIds, locales)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.NewsTranslation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64, []string) error); ok {
+		r1 = rf(ctx, newsIds, locales)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_GetTranslations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTranslations'
+type INewsRepository_GetTranslations_Call struct {
+	*mock.Call
+}
+
+// GetTranslations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - newsIds []int64
+//   - locales []string
+func (_e *INewsRepository_Expecter) GetTranslations(ctx interface{}, newsIds interface{}, locales interface{}) *INewsRepository_GetTranslations_Call {
+	return &INewsRepository_GetTranslations_Call{Call: _e.mock.On("GetTranslations", ctx, newsIds, locales)}
+}
+
+func (_c *INewsRepository_GetTranslations_Call) Run(run func(ctx context.Context, newsIds []int64, locales []string)) *INewsRepository_GetTranslations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_GetTranslations_Call) Return(_a0 []models.NewsTranslation, _a1 error) *INewsRepository_GetTranslations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_GetTranslations_Call) RunAndReturn(run func(context.Context, []int64, []string) ([]models.NewsTranslation, error)) *INewsRepository_GetTranslations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImportNews provides a mock function with given fields: ctx, items, dryRun
+func (_m *INewsRepository) ImportNews(ctx context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error) {
+	ret := _m.Called(ctx, items, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportNews")
+	}
+
+	var r0 []models.NewsImportResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.NewsImportItem, bool) ([]models.NewsImportResult, error)); ok {
+		return rf(ctx, items, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.NewsImportItem, bool) []models.NewsImportResult); ok {
+		r0 = rf(ctx, items, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.NewsImportResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.NewsImportItem, bool) error); ok {
+		r1 = rf(ctx, items, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// INewsRepository_ImportNews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportNews'
+type INewsRepository_ImportNews_Call struct {
+	*mock.Call
+}
+
+// ImportNews is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []models.NewsImportItem
+//   - dryRun bool
+func (_e *INewsRepository_Expecter) ImportNews(ctx interface{}, items interface{}, dryRun interface{}) *INewsRepository_ImportNews_Call {
+	return &INewsRepository_ImportNews_Call{Call: _e.mock.On("ImportNews", ctx, items, dryRun)}
+}
+
+func (_c *INewsRepository_ImportNews_Call) Run(run func(ctx context.Context, items []models.NewsImportItem, dryRun bool)) *INewsRepository_ImportNews_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.NewsImportItem), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_ImportNews_Call) Return(_a0 []models.NewsImportResult, _a1 error) *INewsRepository_ImportNews_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *INewsRepository_ImportNews_Call) RunAndReturn(run func(context.Context, []models.NewsImportItem, bool) ([]models.NewsImportResult, error)) *INewsRepository_ImportNews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateNews provides a mock function with given fields: ctx, newsId, updateFields, categories
+func (_m *INewsRepository) UpdateNews(ctx context.Context, newsId int64, updateFields map[string]interface{}, categories models.CategoryChanges) error {
+	ret := _m.Called(ctx, newsId, updateFields, categories)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateNews")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64, map[string]interface{}, *[]uint64) error); ok {
-		r0 = rf(newsId, updateFields, categories)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, map[string]interface{}, models.CategoryChanges) error); ok {
+		r0 = rf(ctx, newsId, updateFields, categories)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -104,16 +777,17 @@ type INewsRepository_UpdateNews_Call struct {
 }
 
 // UpdateNews is a helper method to define mock.On call
-//   - newsId uint64
+//   - ctx context.Context
+//   - newsId int64
 //   - updateFields map[string]interface{}
-//   - categories *[]uint64
-func (_e *INewsRepository_Expecter) UpdateNews(newsId interface{}, updateFields interface{}, categories interface{}) *INewsRepository_UpdateNews_Call {
-	return &INewsRepository_UpdateNews_Call{Call: _e.mock.On("UpdateNews", newsId, updateFields, categories)}
+//   - categories models.CategoryChanges
+func (_e *INewsRepository_Expecter) UpdateNews(ctx interface{}, newsId interface{}, updateFields interface{}, categories interface{}) *INewsRepository_UpdateNews_Call {
+	return &INewsRepository_UpdateNews_Call{Call: _e.mock.On("UpdateNews", ctx, newsId, updateFields, categories)}
 }
 
-func (_c *INewsRepository_UpdateNews_Call) Run(run func(newsId uint64, updateFields map[string]interface{}, categories *[]uint64)) *INewsRepository_UpdateNews_Call {
+func (_c *INewsRepository_UpdateNews_Call) Run(run func(ctx context.Context, newsId int64, updateFields map[string]interface{}, categories models.CategoryChanges)) *INewsRepository_UpdateNews_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint64), args[1].(map[string]interface{}), args[2].(*[]uint64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(map[string]interface{}), args[3].(models.CategoryChanges))
 	})
 	return _c
 }
@@ -123,7 +797,56 @@ func (_c *INewsRepository_UpdateNews_Call) Return(_a0 error) *INewsRepository_Up
 	return _c
 }
 
-func (_c *INewsRepository_UpdateNews_Call) RunAndReturn(run func(uint64, map[string]interface{}, *[]uint64) error) *INewsRepository_UpdateNews_Call {
+func (_c *INewsRepository_UpdateNews_Call) RunAndReturn(run func(context.Context, int64, map[string]interface{}, models.CategoryChanges) error) *INewsRepository_UpdateNews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTranslation provides a mock function with given fields: ctx, newsId, locale, updateFields
+func (_m *INewsRepository) UpdateTranslation(ctx context.Context, newsId int64, locale string, updateFields map[string]interface{}) error {
+	ret := _m.Called(ctx, newsId, locale, updateFields)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTranslation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, map[string]interface{}) error); ok {
+		r0 = rf(ctx, newsId, locale, updateFields)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// INewsRepository_UpdateTranslation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTranslation'
+type INewsRepository_UpdateTranslation_Call struct {
+	*mock.Call
+}
+
+// UpdateTranslation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - newsId int64
+//   - locale string
+//   - updateFields map[string]interface{}
+func (_e *INewsRepository_Expecter) UpdateTranslation(ctx interface{}, newsId interface{}, locale interface{}, updateFields interface{}) *INewsRepository_UpdateTranslation_Call {
+	return &INewsRepository_UpdateTranslation_Call{Call: _e.mock.On("UpdateTranslation", ctx, newsId, locale, updateFields)}
+}
+
+func (_c *INewsRepository_UpdateTranslation_Call) Run(run func(ctx context.Context, newsId int64, locale string, updateFields map[string]interface{})) *INewsRepository_UpdateTranslation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(map[string]interface{}))
+	})
+	return _c
+}
+
+func (_c *INewsRepository_UpdateTranslation_Call) Return(_a0 error) *INewsRepository_UpdateTranslation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *INewsRepository_UpdateTranslation_Call) RunAndReturn(run func(context.Context, int64, string, map[string]interface{}) error) *INewsRepository_UpdateTranslation_Call {
 	_c.Call.Return(run)
 	return _c
 }
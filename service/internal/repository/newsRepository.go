@@ -3,234 +3,1213 @@ package repository
 import (
 	"context"
 	"database/sql"
-	_ "embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"service/internal/apperrors"
+	"service/internal/configs"
+	"service/internal/metrics"
 	"service/internal/models"
+	"service/pkg/db"
+	"time"
 
-	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/reform.v1"
 )
 
-var (
-	//go:embed sql/select_news_by_limit_and_offset.sql
-	SqlSelectNewsByLimitAndOffset string
-	//go:embed sql/delete_news_categories.sql
-	SqlDeleteNewsCategories string
-	//go:embed sql/insert_news_categories.sql
-	SqlInsertNewsCategories string
-)
+// exportBatchSize - сколько новостей ExportNews читает одним запросом
+const exportBatchSize = 500
+
+// newsQueries - SQL запросы репозитория новостей для драйвера базы
+type newsQueries struct {
+	selectNewsByLimitAndOffset string
+	selectNewsByID             string
+	selectNewsBySlug           string
+	selectNewsAfterID          string
+	replaceNewsCategories      []string
+	addNewsCategories          string
+	removeNewsCategories       string
+	deleteNewsCategories       string
+	selectExistingExternalIDs  string
+	selectNewsSlugsByPrefix    string
+	insertNewsSlug             string
+	deleteNewsSlugs            string
+	insertNewsTranslation      string
+	updateNewsTranslation      string
+	selectNewsTranslation      string
+	selectNewsTranslations     string
+	deleteNewsTranslations     string
+	selectNewsByTags           string
+	insertTags                 string
+	addNewsTags                string
+	deleteOtherNewsTags        string
+	deleteNewsTags             string
+	selectTagsByPrefix         string
+	insertMedia                string
+	selectMediaByID            string
+	deleteNewsMedia            string
+}
+
+func newNewsQueries(d dialect) newsQueries {
+	q := newsQueries{
+		selectNewsByLimitAndOffset: d.query("select_news_by_limit_and_offset.sql"),
+		selectNewsByID:             d.query("select_news_by_id.sql"),
+		selectNewsBySlug:           d.query("select_news_by_slug.sql"),
+		selectNewsAfterID:          d.query("select_news_after_id.sql"),
+		addNewsCategories:          d.query("add_news_categories.sql"),
+		removeNewsCategories:       d.query("remove_news_categories.sql"),
+		deleteNewsCategories:       d.query("delete_news_categories.sql"),
+		selectExistingExternalIDs:  d.query("select_existing_external_ids.sql"),
+		selectNewsSlugsByPrefix:    d.query("select_news_slugs_by_prefix.sql"),
+		insertNewsSlug:             d.query("insert_news_slug.sql"),
+		deleteNewsSlugs:            d.query("delete_news_slugs.sql"),
+		insertNewsTranslation:      d.query("insert_news_translation.sql"),
+		updateNewsTranslation:      d.query("update_news_translation.sql"),
+		selectNewsTranslation:      d.query("select_news_translation.sql"),
+		selectNewsTranslations:     d.query("select_news_translations.sql"),
+		deleteNewsTranslations:     d.query("delete_news_translations.sql"),
+		selectNewsByTags:           d.query("select_news_by_tags.sql"),
+		insertTags:                 d.query("insert_tags.sql"),
+		addNewsTags:                d.query("add_news_tags.sql"),
+		deleteOtherNewsTags:        d.query("delete_other_news_tags.sql"),
+		deleteNewsTags:             d.query("delete_news_tags.sql"),
+		selectTagsByPrefix:         d.query("select_tags_by_prefix.sql"),
+		insertMedia:                d.query("insert_media.sql"),
+		selectMediaByID:            d.query("select_media_by_id.sql"),
+		deleteNewsMedia:            d.query("delete_news_media.sql"),
+	}
+
+	// В SQLite нет изменяющих данные CTE, поэтому замена - это удаление лишних и добавление недостающих
+	if d.driver == configs.DriverSQLite {
+		q.replaceNewsCategories = []string{d.query("delete_other_news_categories.sql"), q.addNewsCategories}
+	} else {
+		q.replaceNewsCategories = []string{d.query("replace_news_categories.sql")}
+	}
+
+	return q
+}
 
 //go:generate mockery --name=INewsRepository --output=mocks --outpkg=mocks --case=snake --with-expecter
 type INewsRepository interface {
-	GetNews(limit, offset int64) ([]models.NewsWithCategories, error)
-	CreateNews(createForm models.NewsCreateForm) (int64, error)
-	UpdateNews(newsId int64, updateFields map[string]interface{}, categories *[]int64) error
+	GetNews(ctx context.Context, limit, offset int64, filter models.NewsFilter) ([]models.NewsWithCategories, error)
+	GetNewsByID(ctx context.Context, newsId int64) (models.NewsWithCategories, error)
+	GetNewsBySlug(ctx context.Context, slug string) (models.NewsWithCategories, error)
+	CreateNews(ctx context.Context, createForm models.NewsCreateForm) (int64, error)
+	UpdateNews(ctx context.Context, newsId int64, updateFields map[string]interface{}, categories models.CategoryChanges) error
+	ApplyBatch(ctx context.Context, items []models.NewsBatchItem, atomic bool) ([]models.NewsBatchResult, error)
+	DeleteNews(ctx context.Context, newsId int64) ([]models.Media, error)
+	ExportNews(ctx context.Context, fn func(models.NewsWithCategories) error) error
+	ImportNews(ctx context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error)
+	CreateTranslation(ctx context.Context, newsId int64, locale string, createForm models.NewsTranslationCreateForm) error
+	UpdateTranslation(ctx context.Context, newsId int64, locale string, updateFields map[string]interface{}) error
+	GetTranslations(ctx context.Context, newsIds []int64, locales []string) ([]models.NewsTranslation, error)
+	GetTags(ctx context.Context, prefix string, limit int64) ([]models.TagCount, error)
+	CreateMedia(ctx context.Context, media *models.Media) error
+	GetMedia(ctx context.Context, mediaId int64) (models.Media, error)
 }
 
+// NewsRepository пишет и читает в транзакциях только из основной базы,
+// а одиночные чтения списка и новости отправляет в реплики, если они есть.
+// Запросы берутся для диалекта db: Postgres или SQLite.
 type NewsRepository struct {
-	db  *reform.DB
-	log *logrus.Logger
-	ctx context.Context
+	db       *reform.DB
+	replicas *db.Replicas
+	dialect  dialect
+	queries  newsQueries
+	log      *logrus.Logger
 }
 
-func NewNewsRepository(db *reform.DB, log *logrus.Logger, ctx context.Context) INewsRepository {
+func NewNewsRepository(db *reform.DB, replicas *db.Replicas, log *logrus.Logger) INewsRepository {
+	d := dialectOf(db)
 	return &NewsRepository{
-		db:  db,
-		log: log,
-		ctx: ctx,
+		db:       db,
+		replicas: replicas,
+		dialect:  d,
+		queries:  newNewsQueries(d),
+		log:      log,
 	}
 }
 
-func (r *NewsRepository) GetNews(limit, offset int64) ([]models.NewsWithCategories, error) {
+// GetNews возвращает страницу новостей по убыванию id, с тегами в filter - только новости со всеми этими тегами
+func (r *NewsRepository) GetNews(ctx context.Context, limit, offset int64, filter models.NewsFilter) ([]models.NewsWithCategories, error) {
 	const op = "repository.news.GetNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	// SQLite считает отрицательный LIMIT отсутствием лимита, отвечаем как Postgres
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("%s: limit and offset must not be negative", op)
+	}
 
-	rows, err := r.db.QueryContext(r.ctx, SqlSelectNewsByLimitAndOffset, limit, offset)
+	query, args := r.queries.selectNewsByLimitAndOffset, []interface{}{limit, offset}
+	if len(filter.Tags) > 0 {
+		query, args = r.queries.selectNewsByTags, append(args, r.dialect.array(filter.Tags), len(filter.Tags))
+	}
+
+	newsList, err := r.selectNews(ctx, r.reader(ctx), query, args...)
 	if err != nil {
-		r.log.WithError(err).WithFields(logrus.Fields{
+		r.log.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"limit":  limit,
 			"offset": offset,
+			"tags":   filter.Tags,
 		}).Error("Failed to select news")
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	defer rows.Close()
 
-	var newsList []models.NewsWithCategories
-	for rows.Next() {
-		var n models.NewsWithCategories
-		var categories []int64
+	return newsList, nil
+}
 
-		if err = rows.Scan(&n.ID, &n.Title, &n.Content, pq.Array(&categories)); err != nil {
-			r.log.WithError(err).Error("Failed to scan news row")
-			return nil, fmt.Errorf("%s: failed to scan row: %w", op, err)
-		}
+func (r *NewsRepository) GetNewsByID(ctx context.Context, newsId int64) (models.NewsWithCategories, error) {
+	const op = "repository.news.GetNewsByID"
+	defer metrics.ObserveRepository(op, time.Now())
 
-		if categories == nil {
-			categories = []int64{}
-		}
-		n.Categories = categories
+	newsList, err := r.selectNews(ctx, r.reader(ctx), r.queries.selectNewsByID, newsId)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to select news")
+		return models.NewsWithCategories{}, fmt.Errorf("%s: %w", op, err)
+	}
 
-		newsList = append(newsList, n)
+	if len(newsList) == 0 {
+		r.log.WithContext(ctx).WithField("news_id", newsId).Warn("News not found")
+		return models.NewsWithCategories{}, apperrors.NewNotFound("News not found")
 	}
 
-	if err = rows.Err(); err != nil {
-		r.log.WithError(err).Error("Error iterating news rows")
-		return nil, fmt.Errorf("%s: %w", op, err)
+	return newsList[0], nil
+}
+
+// GetNewsBySlug ищет новость по текущему или прежнему slug, текущий возвращается в Slug
+func (r *NewsRepository) GetNewsBySlug(ctx context.Context, slug string) (models.NewsWithCategories, error) {
+	const op = "repository.news.GetNewsBySlug"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	newsList, err := r.selectNews(ctx, r.reader(ctx), r.queries.selectNewsBySlug, slug)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("slug", slug).Error("Failed to select news")
+		return models.NewsWithCategories{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return newsList, nil
+	if len(newsList) == 0 {
+		r.log.WithContext(ctx).WithField("slug", slug).Warn("News not found")
+		return models.NewsWithCategories{}, apperrors.NewNotFound("News not found")
+	}
+
+	return newsList[0], nil
 }
 
-func (r *NewsRepository) CreateNews(createForm models.NewsCreateForm) (int64, error) {
+func (r *NewsRepository) CreateNews(ctx context.Context, createForm models.NewsCreateForm) (int64, error) {
 	const op = "repository.news.CreateNews"
+	defer metrics.ObserveRepository(op, time.Now())
 
-	tx, err := r.db.Begin()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		r.log.WithError(err).Error("Failed to begin transaction")
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
 		return 0, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
 	}
-	defer r.rollbackOnError(tx, op)
+	defer r.rollbackOnError(ctx, tx, op)
 
-	news := &models.News{
-		Title:   createForm.Title,
-		Content: createForm.Content,
+	newsID, err := r.createNews(ctx, tx, createForm, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err = tx.Save(news); err != nil {
-		r.log.WithError(err).WithField("title", createForm.Title).Error("Failed to insert news")
-		return 0, fmt.Errorf("%s: failed to insert news: %w", op, err)
+	if err = tx.Commit(); err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return 0, fmt.Errorf("%s: failed to commit: %w", op, err)
 	}
 
-	newsID := news.ID
+	r.log.WithContext(ctx).WithField("news_id", newsID).Info("News created successfully")
+	return newsID, nil
+}
 
-	if createForm.Categories != nil && len(*createForm.Categories) > 0 {
-		if err = r.insertCategories(tx, newsID, *createForm.Categories); err != nil {
-			return 0, fmt.Errorf("%s: %w", op, err)
+func (r *NewsRepository) UpdateNews(ctx context.Context, newsId int64, updateFields map[string]interface{}, categories models.CategoryChanges) error {
+	const op = "repository.news.UpdateNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer r.rollbackOnError(ctx, tx, op)
+
+	if err = r.updateNews(ctx, tx, newsId, updateFields, categories); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return fmt.Errorf("%s: failed to commit: %w", op, err)
+	}
+
+	r.log.WithContext(ctx).WithField("news_id", newsId).Info("News updated successfully")
+	return nil
+}
+
+// DeleteNews удаляет новость вместе с её категориями, тегами, slug, переводами и описаниями вложений.
+// Удалённые вложения возвращаются, чтобы вызывающий удалил их файлы из хранилища.
+func (r *NewsRepository) DeleteNews(ctx context.Context, newsId int64) ([]models.Media, error) {
+	const op = "repository.news.DeleteNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer r.rollbackOnError(ctx, tx, op)
+
+	// Блокировка не даёт параллельной загрузке прикрепить файл, который не попадёт в список удалённых
+	news, err := r.findNewsByID(ctx, tx, newsId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, r.queries.deleteNewsCategories, newsId); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to delete categories")
+		return nil, fmt.Errorf("%s: failed to delete categories: %w", op, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, r.queries.deleteNewsTags, newsId); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to delete tags")
+		return nil, fmt.Errorf("%s: failed to delete tags: %w", op, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, r.queries.deleteNewsSlugs, newsId); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to delete slugs")
+		return nil, fmt.Errorf("%s: failed to delete slugs: %w", op, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, r.queries.deleteNewsTranslations, newsId); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to delete translations")
+		return nil, fmt.Errorf("%s: failed to delete translations: %w", op, err)
+	}
+
+	media, err := r.scanMedia(tx.QueryContext(ctx, r.queries.deleteNewsMedia, newsId))
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to delete media")
+		return nil, fmt.Errorf("%s: failed to delete media: %w", op, err)
+	}
+
+	if err = tx.Delete(news); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to delete news")
+		return nil, fmt.Errorf("%s: failed to delete: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return nil, fmt.Errorf("%s: failed to commit: %w", op, err)
+	}
+
+	r.log.WithContext(ctx).WithField("news_id", newsId).Info("News deleted successfully")
+	return media, nil
+}
+
+// ExportNews читает все новости по возрастанию id пачками по exportBatchSize и передаёт каждую в fn,
+// не накапливая их в памяти и не держа соединение, пока fn пишет клиенту. Ошибка fn прерывает выгрузку.
+func (r *NewsRepository) ExportNews(ctx context.Context, fn func(models.NewsWithCategories) error) error {
+	const op = "repository.news.ExportNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	var lastID int64
+	for {
+		newsList, err := r.selectNews(ctx, r.reader(ctx), r.queries.selectNewsAfterID, lastID, exportBatchSize)
+		if err != nil {
+			r.log.WithContext(ctx).WithError(err).WithField("after_id", lastID).Error("Failed to select news")
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, n := range newsList {
+			if err = fn(n); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
 		}
+
+		if len(newsList) < exportBatchSize {
+			return nil
+		}
+		lastID = newsList[len(newsList)-1].ID
+	}
+}
+
+// CreateTranslation добавляет перевод новости на язык locale.
+// Перевод - часть новости: его появление поднимает версию и время изменения новости.
+func (r *NewsRepository) CreateTranslation(ctx context.Context, newsId int64, locale string, createForm models.NewsTranslationCreateForm) error {
+	const op = "repository.news.CreateTranslation"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer r.rollbackOnError(ctx, tx, op)
+
+	news, err := r.findNewsByID(ctx, tx, newsId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	translation := models.NewsTranslation{
+		NewsID:        newsId,
+		Locale:        locale,
+		Title:         createForm.Title,
+		Content:       createForm.Content,
+		ContentFormat: createForm.ContentFormat,
+		UpdatedAt:     time.Now().UTC(),
+	}
+	translation.RenderContent()
+
+	result, err := tx.ExecContext(ctx, r.queries.insertNewsTranslation, translation.NewsID, translation.Locale,
+		translation.Title, translation.Content, translation.ContentFormat, translation.ContentHTML, translation.UpdatedAt)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to insert translation")
+		return fmt.Errorf("%s: failed to insert translation: %w", op, err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to insert translation: %w", op, err)
+	}
+	if inserted == 0 {
+		r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "locale": locale}).Warn("Translation already exists")
+		return apperrors.NewConflict("Translation already exists")
+	}
+
+	if err = r.touchNews(ctx, tx, news, translation.UpdatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err = tx.Commit(); err != nil {
-		r.log.WithError(err).Error("Failed to commit transaction")
-		return 0, fmt.Errorf("%s: failed to commit: %w", op, err)
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return fmt.Errorf("%s: failed to commit: %w", op, err)
 	}
 
-	r.log.WithField("news_id", newsID).Info("News created successfully")
-	return newsID, nil
+	r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "locale": locale}).Info("Translation created successfully")
+	return nil
 }
 
-func (r *NewsRepository) UpdateNews(newsId int64, updateFields map[string]interface{}, categories *[]int64) error {
-	const op = "repository.news.UpdateNews"
+// UpdateTranslation меняет поля перевода, ключи updateFields те же, что у UpdateNews
+func (r *NewsRepository) UpdateTranslation(ctx context.Context, newsId int64, locale string, updateFields map[string]interface{}) error {
+	const op = "repository.news.UpdateTranslation"
+	defer metrics.ObserveRepository(op, time.Now())
 
-	tx, err := r.db.Begin()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		r.log.WithError(err).Error("Failed to begin transaction")
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
 		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
 	}
-	defer r.rollbackOnError(tx, op)
+	defer r.rollbackOnError(ctx, tx, op)
 
-	news, err := r.findNewsByID(tx, newsId)
+	// Блокировка новости сериализует и изменения её переводов
+	news, err := r.findNewsByID(ctx, tx, newsId)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if len(updateFields) > 0 {
-		if title, ok := updateFields["title"]; ok {
-			news.Title = *title.(*string)
+	var translation models.NewsTranslation
+	err = tx.QueryRowContext(ctx, r.queries.selectNewsTranslation, newsId, locale).Scan(&translation.NewsID, &translation.Locale,
+		&translation.Title, &translation.Content, &translation.ContentFormat, &translation.ContentHTML, &translation.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "locale": locale}).Warn("Translation not found")
+		return apperrors.NewNotFound("Translation not found")
+	}
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to select translation")
+		return fmt.Errorf("%s: failed to select translation: %w", op, err)
+	}
+
+	applyTranslationFields(&translation, updateFields)
+	translation.UpdatedAt = time.Now().UTC()
+
+	_, err = tx.ExecContext(ctx, r.queries.updateNewsTranslation, translation.NewsID, translation.Locale,
+		translation.Title, translation.Content, translation.ContentFormat, translation.ContentHTML, translation.UpdatedAt)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to update translation")
+		return fmt.Errorf("%s: failed to update translation: %w", op, err)
+	}
+
+	if err = r.touchNews(ctx, tx, news, translation.UpdatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return fmt.Errorf("%s: failed to commit: %w", op, err)
+	}
+
+	r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "locale": locale}).Info("Translation updated successfully")
+	return nil
+}
+
+// GetTranslations возвращает переводы новостей newsIds на языки locales в произвольном порядке
+func (r *NewsRepository) GetTranslations(ctx context.Context, newsIds []int64, locales []string) ([]models.NewsTranslation, error) {
+	const op = "repository.news.GetTranslations"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	translations := []models.NewsTranslation{}
+	if len(newsIds) == 0 || len(locales) == 0 {
+		return translations, nil
+	}
+
+	rows, err := r.reader(ctx).QueryContext(ctx, r.queries.selectNewsTranslations, r.dialect.array(newsIds), r.dialect.array(locales))
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to select translations")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.NewsTranslation
+		if err = rows.Scan(&t.NewsID, &t.Locale, &t.Title, &t.Content, &t.ContentFormat, &t.ContentHTML, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan translation: %w", op, err)
 		}
+		translations = append(translations, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-		if content, ok := updateFields["content"]; ok {
-			news.Content = *content.(*string)
+	return translations, nil
+}
+
+// GetTags возвращает теги с префиксом prefix, которые есть хотя бы у одной новости,
+// сначала самые используемые. Теги, снятые со всех новостей, в подсказки не попадают.
+func (r *NewsRepository) GetTags(ctx context.Context, prefix string, limit int64) ([]models.TagCount, error) {
+	const op = "repository.news.GetTags"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	rows, err := r.reader(ctx).QueryContext(ctx, r.queries.selectTagsByPrefix, escapeLike(prefix)+"%", limit)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("prefix", prefix).Error("Failed to select tags")
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if err = rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan tag: %w", op, err)
 		}
+		tags = append(tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-		if err = tx.Update(news); err != nil {
-			r.log.WithError(err).WithField("news_id", newsId).Error("Failed to update news")
-			return fmt.Errorf("%s: failed to update: %w", op, err)
+	return tags, nil
+}
+
+// CreateMedia сохраняет описание загруженного файла и заполняет media.ID.
+// Вложение - часть новости: его появление поднимает версию и время изменения новости.
+func (r *NewsRepository) CreateMedia(ctx context.Context, media *models.Media) error {
+	const op = "repository.news.CreateMedia"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer r.rollbackOnError(ctx, tx, op)
+
+	news, err := r.findNewsByID(ctx, tx, media.NewsID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	media.CreatedAt = time.Now().UTC()
+	err = tx.QueryRowContext(ctx, r.queries.insertMedia, media.NewsID, media.StorageKey, media.MimeType,
+		media.Size, media.Checksum, media.AltText, media.CreatedAt).Scan(&media.ID)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", media.NewsID).Error("Failed to insert media")
+		return fmt.Errorf("%s: failed to insert media: %w", op, err)
+	}
+
+	if err = r.touchNews(ctx, tx, news, media.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return fmt.Errorf("%s: failed to commit: %w", op, err)
+	}
+
+	r.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": media.NewsID, "media_id": media.ID}).Info("Media created successfully")
+	return nil
+}
+
+func (r *NewsRepository) GetMedia(ctx context.Context, mediaId int64) (models.Media, error) {
+	const op = "repository.news.GetMedia"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	media, err := r.scanMedia(r.reader(ctx).QueryContext(ctx, r.queries.selectMediaByID, mediaId))
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("media_id", mediaId).Error("Failed to select media")
+		return models.Media{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(media) == 0 {
+		r.log.WithContext(ctx).WithField("media_id", mediaId).Warn("Media not found")
+		return models.Media{}, apperrors.NewNotFound("Media not found")
+	}
+
+	return media[0], nil
+}
+
+// touchNews поднимает версию и время изменения новости, у которой изменился перевод или вложения
+func (r *NewsRepository) touchNews(ctx context.Context, tx *reform.TX, news *models.News, updatedAt time.Time) error {
+	news.Version++
+	news.UpdatedAt = updatedAt
+
+	if err := tx.UpdateColumns(news, "version", "updated_at"); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", news.ID).Error("Failed to update news")
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+// ImportNews создаёт или обновляет новости по внешнему id, каждая строка в своей транзакции.
+// В режиме dryRun ничего не пишет, а только сообщает, будет ли строка создана или обновлена.
+func (r *NewsRepository) ImportNews(ctx context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error) {
+	const op = "repository.news.ImportNews"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	if dryRun {
+		results, err := r.planImport(ctx, items)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
+		return results, nil
 	}
 
-	if categories != nil {
-		if err = r.updateCategories(tx, newsId, *categories); err != nil {
-			return fmt.Errorf("%s: %w", op, err)
+	results := make([]models.NewsImportResult, 0, len(items))
+	for _, item := range items {
+		results = append(results, r.importItemInTx(ctx, item))
+	}
+
+	return results, nil
+}
+
+// planImport определяет действие для каждой строки одним запросом по всем внешним id
+func (r *NewsRepository) planImport(ctx context.Context, items []models.NewsImportItem) ([]models.NewsImportResult, error) {
+	externalIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.ExternalID != "" {
+			externalIDs = append(externalIDs, item.ExternalID)
 		}
 	}
 
+	existing := make(map[string]struct{})
+	if len(externalIDs) > 0 {
+		rows, err := r.db.QueryContext(ctx, r.queries.selectExistingExternalIDs, r.dialect.array(externalIDs))
+		if err != nil {
+			r.log.WithContext(ctx).WithError(err).Error("Failed to select external ids")
+			return nil, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var externalID string
+			if err = rows.Scan(&externalID); err != nil {
+				r.log.WithContext(ctx).WithError(err).Error("Failed to scan external id")
+				return nil, err
+			}
+			existing[externalID] = struct{}{}
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]models.NewsImportResult, 0, len(items))
+	for _, item := range items {
+		action := models.ImportActionCreate
+		if _, ok := existing[item.ExternalID]; ok {
+			action = models.ImportActionUpdate
+		}
+		// Повтор внешнего id в одном файле - вторая строка обновит новость, созданную первой
+		if item.ExternalID != "" {
+			existing[item.ExternalID] = struct{}{}
+		}
+		results = append(results, models.NewsImportResult{
+			Line:       item.Line,
+			ExternalID: item.ExternalID,
+			Action:     action,
+			Success:    true,
+		})
+	}
+
+	return results, nil
+}
+
+func (r *NewsRepository) importItemInTx(ctx context.Context, item models.NewsImportItem) models.NewsImportResult {
+	const op = "repository.news.ImportNews"
+
+	result := models.NewsImportResult{Line: item.Line, ExternalID: item.ExternalID}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
+		return importErrorResult(result, err)
+	}
+	defer r.rollbackOnError(ctx, tx, op)
+
+	result.Id, result.Action, err = r.importItem(ctx, tx, item)
+	if err != nil {
+		return importErrorResult(result, err)
+	}
+
 	if err = tx.Commit(); err != nil {
-		r.log.WithError(err).Error("Failed to commit transaction")
-		return fmt.Errorf("%s: failed to commit: %w", op, err)
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return importErrorResult(result, err)
+	}
+
+	result.Success = true
+	return result
+}
+
+func (r *NewsRepository) importItem(ctx context.Context, tx *reform.TX, item models.NewsImportItem) (int64, string, error) {
+	if item.ExternalID == "" {
+		id, err := r.createNews(ctx, tx, item.Form, nil)
+		return id, models.ImportActionCreate, err
+	}
+
+	record, err := tx.SelectOneFrom(models.NewsTable, "WHERE external_id = "+r.db.Placeholder(1)+r.dialect.forUpdate, item.ExternalID)
+	if errors.Is(err, reform.ErrNoRows) {
+		externalID := item.ExternalID
+		id, err := r.createNews(ctx, tx, item.Form, &externalID)
+		return id, models.ImportActionCreate, err
+	}
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("external_id", item.ExternalID).Error("Failed to find news by external id")
+		return 0, models.ImportActionUpdate, fmt.Errorf("failed to find news: %w", err)
+	}
+
+	news := record.(*models.News)
+	updateFields := map[string]interface{}{
+		"title":          &item.Form.Title,
+		"content":        &item.Form.Content,
+		"content_format": &item.Form.ContentFormat,
+	}
+	// Без категорий и тегов в строке категории и теги новости не трогаем
+	categories := models.CategoryChanges{Replace: item.Form.Categories}
+	if item.Form.Tags != nil {
+		updateFields["tags"] = &item.Form.Tags
+	}
+
+	return news.ID, models.ImportActionUpdate, r.updateNews(ctx, tx, news.ID, updateFields, categories)
+}
+
+// ApplyBatch выполняет операции пакета. В атомарном режиме все операции идут в одной транзакции
+// и первая ошибка откатывает весь пакет, иначе каждая операция выполняется в своей транзакции.
+func (r *NewsRepository) ApplyBatch(ctx context.Context, items []models.NewsBatchItem, atomic bool) ([]models.NewsBatchResult, error) {
+	const op = "repository.news.ApplyBatch"
+	defer metrics.ObserveRepository(op, time.Now())
+
+	if !atomic {
+		results := make([]models.NewsBatchResult, 0, len(items))
+		for _, item := range items {
+			results = append(results, r.applyBatchItemInTx(ctx, item))
+		}
+		return results, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer r.rollbackOnError(ctx, tx, op)
+
+	results := make([]models.NewsBatchResult, 0, len(items))
+	for _, item := range items {
+		result := r.applyBatchItem(ctx, tx, item)
+		results = append(results, result)
+
+		if !result.Success {
+			r.log.WithContext(ctx).WithField("index", item.Index).Warn("Batch item failed, rolling back batch")
+			return rollbackBatchResults(results, items), nil
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return nil, fmt.Errorf("%s: failed to commit: %w", op, err)
+	}
+
+	r.log.WithContext(ctx).WithField("items", len(items)).Info("News batch applied successfully")
+	return results, nil
+}
+
+func (r *NewsRepository) applyBatchItemInTx(ctx context.Context, item models.NewsBatchItem) models.NewsBatchResult {
+	const op = "repository.news.ApplyBatch"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to begin transaction")
+		return batchErrorResult(item, err)
+	}
+	defer r.rollbackOnError(ctx, tx, op)
+
+	result := r.applyBatchItem(ctx, tx, item)
+	if !result.Success {
+		return result
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.WithContext(ctx).WithError(err).Error("Failed to commit transaction")
+		return batchErrorResult(item, err)
+	}
+
+	return result
+}
+
+func (r *NewsRepository) applyBatchItem(ctx context.Context, tx *reform.TX, item models.NewsBatchItem) models.NewsBatchResult {
+	switch item.Op {
+	case models.BatchOpCreate:
+		newsID, err := r.createNews(ctx, tx, *item.Create, nil)
+		if err != nil {
+			return batchErrorResult(item, err)
+		}
+		return models.NewsBatchResult{Index: item.Index, Op: item.Op, Id: newsID, Status: 201, Success: true}
+	case models.BatchOpEdit:
+		if err := r.updateNews(ctx, tx, item.NewsID, item.Edit.UpdateFields(), item.Edit.CategoryChanges()); err != nil {
+			return batchErrorResult(item, err)
+		}
+		return models.NewsBatchResult{Index: item.Index, Op: item.Op, Id: item.NewsID, Status: 200, Success: true}
+	default:
+		return batchErrorResult(item, apperrors.NewValidation("unknown operation"))
+	}
+}
+
+func (r *NewsRepository) createNews(ctx context.Context, tx *reform.TX, createForm models.NewsCreateForm, externalID *string) (int64, error) {
+	news := &models.News{
+		Title:         createForm.Title,
+		Content:       createForm.Content,
+		ContentFormat: createForm.ContentFormat,
+		Version:       1,
+		UpdatedAt:     time.Now().UTC(),
+		ExternalID:    externalID,
+	}
+	news.RenderContent()
+
+	if err := tx.Save(news); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("title", createForm.Title).Error("Failed to insert news")
+		return 0, fmt.Errorf("failed to insert news: %w", err)
+	}
+
+	// news_slugs ссылается на новость, поэтому slug закрепляется после вставки
+	slug, err := r.assignSlug(ctx, tx, news.ID, news.Title)
+	if err != nil {
+		return 0, err
+	}
+	news.Slug = slug
+	if err = tx.UpdateColumns(news, "slug"); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", news.ID).Error("Failed to set news slug")
+		return 0, fmt.Errorf("failed to set slug: %w", err)
+	}
+
+	if createForm.Categories != nil && len(*createForm.Categories) > 0 {
+		if err := r.insertCategories(ctx, tx, news.ID, *createForm.Categories); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(createForm.Tags) > 0 {
+		if err := r.replaceTags(ctx, tx, news.ID, createForm.Tags); err != nil {
+			return 0, err
+		}
+	}
+
+	return news.ID, nil
+}
+
+func (r *NewsRepository) updateNews(ctx context.Context, tx *reform.TX, newsId int64, updateFields map[string]interface{}, categories models.CategoryChanges) error {
+	news, err := r.findNewsByID(ctx, tx, newsId)
+	if err != nil {
+		return err
+	}
+
+	if title, ok := updateFields["title"]; ok {
+		news.Title = *title.(*string)
+		// Прежний slug остаётся в news_slugs, и старые адреса перенаправляются на новый.
+		// Заголовок с той же основой оставляет текущий slug: assignSlug вернёт его же.
+		if news.Slug != slugBase(news.Title) {
+			if news.Slug, err = r.assignSlug(ctx, tx, newsId, news.Title); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, contentChanged := updateFields["content"]
+	if contentChanged {
+		news.Content = *content.(*string)
+	}
+
+	format, formatChanged := updateFields["content_format"]
+	if formatChanged {
+		news.ContentFormat = *format.(*string)
+	}
+
+	if contentChanged || formatChanged {
+		news.RenderContent()
+	}
+
+	// Любое изменение, включая категории, поднимает версию новости
+	news.Version++
+	news.UpdatedAt = time.Now().UTC()
+
+	if err = tx.Update(news); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to update news")
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	if !categories.IsEmpty() {
+		if err = r.updateCategories(ctx, tx, newsId, categories); err != nil {
+			return err
+		}
+	}
+
+	if tags, ok := updateFields["tags"]; ok {
+		if err = r.replaceTags(ctx, tx, newsId, *tags.(*[]string)); err != nil {
+			return err
+		}
 	}
 
-	r.log.WithField("news_id", newsId).Info("News updated successfully")
 	return nil
 }
 
-func (r *NewsRepository) findNewsByID(tx *reform.TX, newsId int64) (*models.News, error) {
-	record, err := tx.FindByPrimaryKeyFrom(models.NewsTable, newsId)
+// replaceTags заменяет теги новости на tags, недостающие теги создаются в той же транзакции.
+// Одновременное создание одного тега безопасно: вставка с ON CONFLICT DO NOTHING дождётся
+// другой транзакции, а следующий запрос уже увидит её тег.
+func (r *NewsRepository) replaceTags(ctx context.Context, tx *reform.TX, newsId int64, tags []string) error {
+	if len(tags) > 0 {
+		if _, err := tx.ExecContext(ctx, r.queries.insertTags, r.dialect.array(tags)); err != nil {
+			r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to insert tags")
+			return fmt.Errorf("failed to insert tags: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, r.queries.deleteOtherNewsTags, newsId, r.dialect.array(tags)); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to delete news tags")
+		return fmt.Errorf("failed to delete news tags: %w", err)
+	}
+
+	if len(tags) > 0 {
+		if _, err := tx.ExecContext(ctx, r.queries.addNewsTags, newsId, r.dialect.array(tags)); err != nil {
+			r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to add news tags")
+			return fmt.Errorf("failed to add news tags: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// assignSlug закрепляет за новостью slug из заголовка, добавляя числовой суффикс при совпадении.
+// Slug, который параллельная транзакция заняла между выбором и вставкой, пропускается: вставка
+// с ON CONFLICT DO NOTHING не прерывает транзакцию, и берётся следующий суффикс.
+func (r *NewsRepository) assignSlug(ctx context.Context, tx *reform.TX, newsId int64, title string) (string, error) {
+	base := slugBase(title)
+
+	taken, err := r.selectTakenSlugs(ctx, tx, base)
+	if err != nil {
+		r.log.WithContext(ctx).WithError(err).WithField("slug", base).Error("Failed to select slugs")
+		return "", fmt.Errorf("failed to select slugs: %w", err)
+	}
+
+	for {
+		candidate, owned := slugCandidate(base, taken, newsId)
+		if owned {
+			return candidate, nil
+		}
+
+		result, err := tx.ExecContext(ctx, r.queries.insertNewsSlug, candidate, newsId)
+		if err != nil {
+			r.log.WithContext(ctx).WithError(err).WithField("slug", candidate).Error("Failed to insert slug")
+			return "", fmt.Errorf("failed to insert slug: %w", err)
+		}
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return "", fmt.Errorf("failed to insert slug: %w", err)
+		}
+		if inserted == 1 {
+			return candidate, nil
+		}
+
+		// Занят другой транзакцией: id новостей начинаются с 1, поэтому 0 - чужой slug
+		taken[candidate] = 0
+	}
+}
+
+// selectTakenSlugs возвращает занятые slug с основой base и id их новостей
+func (r *NewsRepository) selectTakenSlugs(ctx context.Context, tx *reform.TX, base string) (map[string]int64, error) {
+	rows, err := tx.QueryContext(ctx, r.queries.selectNewsSlugsByPrefix, base)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taken := make(map[string]int64)
+	for rows.Next() {
+		var slug string
+		var newsID int64
+		if err = rows.Scan(&slug, &newsID); err != nil {
+			return nil, err
+		}
+		taken[slug] = newsID
+	}
+
+	return taken, rows.Err()
+}
+
+// findNewsByID блокирует строку новости до конца транзакции, чтобы параллельные изменения
+// не перезаписывали друг друга и версия росла на каждое изменение
+func (r *NewsRepository) findNewsByID(ctx context.Context, tx *reform.TX, newsId int64) (*models.News, error) {
+	record, err := tx.SelectOneFrom(models.NewsTable, "WHERE id = "+r.db.Placeholder(1)+r.dialect.forUpdate, newsId)
 	if err != nil {
 		if errors.Is(err, reform.ErrNoRows) {
-			r.log.WithField("news_id", newsId).Warn("News not found")
+			r.log.WithContext(ctx).WithField("news_id", newsId).Warn("News not found")
 			return nil, apperrors.NewNotFound("News not found")
 		}
-		r.log.WithError(err).WithField("news_id", newsId).Error("Failed to find news")
+		r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to find news")
 		return nil, fmt.Errorf("failed to find news: %w", err)
 	}
 
 	return record.(*models.News), nil
 }
 
-func (r *NewsRepository) rollbackOnError(tx *reform.TX, op string) {
+// selectNews выполняет запрос новостей, который в каждой строке отдаёт категории, теги и вложения
+// JSON массивами (json_agg в Postgres, json_group_array в SQLite), и разбирает их decodeNewsRelations.
+func (r *NewsRepository) selectNews(ctx context.Context, q *reform.DB, query string, args ...interface{}) ([]models.NewsWithCategories, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var newsList []models.NewsWithCategories
+	for rows.Next() {
+		var (
+			n                       models.NewsWithCategories
+			categories, tags, media []byte
+		)
+		err = rows.Scan(&n.ID, &n.Title, &n.Content, &n.Version, &n.UpdatedAt, &n.ExternalID, &n.ContentFormat, &n.ContentHTML, &n.Slug,
+			&categories, &tags, &media)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err = decodeNewsRelations(&n, categories, tags, media); err != nil {
+			return nil, err
+		}
+		newsList = append(newsList, n)
+	}
+
+	return newsList, rows.Err()
+}
+
+// newsMediaJSON - вложение из JSON массива запроса новостей. У models.Media StorageKey скрыт из JSON,
+// поэтому разбираем в тип с теми же полями без тегов.
+type newsMediaJSON struct {
+	ID         int64
+	NewsID     int64
+	MimeType   string
+	Size       int64
+	Checksum   string
+	AltText    string
+	CreatedAt  time.Time
+	StorageKey string
+}
+
+// decodeNewsRelations разбирает категории, теги и вложения новости. Запросы новостей собирают их
+// JSON массивами в той же строке: одним запросом новость и её связи читаются из одного снимка базы.
+func decodeNewsRelations(n *models.NewsWithCategories, categories, tags, media []byte) error {
+	if err := json.Unmarshal(categories, &n.Categories); err != nil {
+		return fmt.Errorf("failed to decode categories: %w", err)
+	}
+	if err := json.Unmarshal(tags, &n.Tags); err != nil {
+		return fmt.Errorf("failed to decode tags: %w", err)
+	}
+
+	var items []newsMediaJSON
+	if err := json.Unmarshal(media, &items); err != nil {
+		return fmt.Errorf("failed to decode media: %w", err)
+	}
+	n.Media = make([]models.Media, 0, len(items))
+	for _, item := range items {
+		n.Media = append(n.Media, models.Media(item))
+	}
+
+	return nil
+}
+
+// scanMedia читает строки запроса вложений, принимает результат QueryContext целиком
+func (r *NewsRepository) scanMedia(rows *sql.Rows, err error) ([]models.Media, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	media := []models.Media{}
+	for rows.Next() {
+		var m models.Media
+		if err = rows.Scan(&m.ID, &m.NewsID, &m.MimeType, &m.Size, &m.Checksum, &m.AltText, &m.CreatedAt, &m.StorageKey); err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		media = append(media, m)
+	}
+
+	return media, rows.Err()
+}
+
+// reader возвращает реплику для чтения или основную базу, если реплик нет или чтение с мастера принудительно
+func (r *NewsRepository) reader(ctx context.Context) *reform.DB {
+	if replica := r.replicas.Reader(ctx); replica != nil {
+		return replica
+	}
+	return r.db
+}
+
+func (r *NewsRepository) rollbackOnError(ctx context.Context, tx *reform.TX, op string) {
 	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
-		r.log.WithError(err).WithField("operation", op).Error("Failed to rollback transaction")
+		r.log.WithContext(ctx).WithError(err).WithField("operation", op).Error("Failed to rollback transaction")
 	}
 }
 
-func (r *NewsRepository) insertCategories(tx *reform.TX, newsId int64, categoryIDs []int64) error {
+// insertCategories вставляет категории новости одним многострочным INSERT
+func (r *NewsRepository) insertCategories(ctx context.Context, tx *reform.TX, newsId int64, categoryIDs []int64) error {
+	seen := make(map[int64]struct{}, len(categoryIDs))
+	newsCategories := make([]reform.Struct, 0, len(categoryIDs))
 	for _, categoryID := range categoryIDs {
-		newsCategory := &models.NewsCategory{
+		if _, ok := seen[categoryID]; ok {
+			continue
+		}
+		seen[categoryID] = struct{}{}
+
+		newsCategories = append(newsCategories, &models.NewsCategory{
 			NewsId:     newsId,
 			CategoryId: categoryID,
-		}
+		})
+	}
 
-		if err := tx.Save(newsCategory); err != nil {
-			r.log.WithError(err).WithFields(logrus.Fields{
-				"news_id":     newsId,
-				"category_id": categoryID,
-			}).Error("Failed to insert news category")
-			return fmt.Errorf("failed to insert category %d: %w", categoryID, err)
-		}
+	if err := tx.InsertMulti(newsCategories...); err != nil {
+		r.log.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"news_id":    newsId,
+			"categories": categoryIDs,
+		}).Error("Failed to insert news categories")
+		return fmt.Errorf("failed to insert categories: %w", err)
 	}
 
-	r.log.WithFields(logrus.Fields{
+	r.log.WithContext(ctx).WithFields(logrus.Fields{
 		"news_id":    newsId,
-		"categories": len(categoryIDs),
+		"categories": len(newsCategories),
 	}).Debug("Categories inserted")
 
 	return nil
 }
 
-func (r *NewsRepository) updateCategories(tx *reform.TX, newsId int64, categoryIDs []int64) error {
-	if _, err := tx.ExecContext(r.ctx, SqlDeleteNewsCategories, newsId); err != nil {
-		r.log.WithError(err).WithField("news_id", newsId).Error("Failed to delete old categories")
-		return fmt.Errorf("failed to delete old categories: %w", err)
+// updateCategories применяет изменения категорий set-based запросами:
+// замена удаляет лишние и добавляет недостающие строки одним запросом, не трогая остальные
+func (r *NewsRepository) updateCategories(ctx context.Context, tx *reform.TX, newsId int64, changes models.CategoryChanges) error {
+	if changes.Replace != nil {
+		for _, query := range r.queries.replaceNewsCategories {
+			if _, err := tx.ExecContext(ctx, query, newsId, r.dialect.array(*changes.Replace)); err != nil {
+				r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to replace categories")
+				return fmt.Errorf("failed to replace categories: %w", err)
+			}
+		}
 	}
 
-	if len(categoryIDs) > 0 {
-		for _, categoryID := range categoryIDs {
-			if _, err := tx.ExecContext(r.ctx, SqlInsertNewsCategories, newsId, categoryID); err != nil {
-				r.log.WithError(err).WithFields(logrus.Fields{
-					"news_id":     newsId,
-					"category_id": categoryID,
-				}).Error("Failed to insert category")
-				return fmt.Errorf("failed to insert category %d: %w", categoryID, err)
-			}
+	if len(changes.Remove) > 0 {
+		if _, err := tx.ExecContext(ctx, r.queries.removeNewsCategories, newsId, r.dialect.array(changes.Remove)); err != nil {
+			r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to remove categories")
+			return fmt.Errorf("failed to remove categories: %w", err)
 		}
+	}
 
-		r.log.WithFields(logrus.Fields{
-			"news_id":    newsId,
-			"categories": len(categoryIDs),
-		}).Debug("Categories updated")
+	if len(changes.Add) > 0 {
+		if _, err := tx.ExecContext(ctx, r.queries.addNewsCategories, newsId, r.dialect.array(changes.Add)); err != nil {
+			r.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to add categories")
+			return fmt.Errorf("failed to add categories: %w", err)
+		}
 	}
 
+	r.log.WithContext(ctx).WithField("news_id", newsId).Debug("Categories updated")
+
 	return nil
 }
+
+// applyTranslationFields меняет поля перевода и пересобирает HTML, если изменились текст или формат
+func applyTranslationFields(t *models.NewsTranslation, updateFields map[string]interface{}) {
+	if title, ok := updateFields["title"]; ok {
+		t.Title = *title.(*string)
+	}
+
+	content, contentChanged := updateFields["content"]
+	if contentChanged {
+		t.Content = *content.(*string)
+	}
+
+	format, formatChanged := updateFields["content_format"]
+	if formatChanged {
+		t.ContentFormat = *format.(*string)
+	}
+
+	if contentChanged || formatChanged {
+		t.RenderContent()
+	}
+}
+
+func batchErrorResult(item models.NewsBatchItem, err error) models.NewsBatchResult {
+	result := models.NewsBatchResult{
+		Index:  item.Index,
+		Op:     item.Op,
+		Id:     item.NewsID,
+		Status: 500,
+		Error:  "Internal server error",
+	}
+
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		result.Status = appErr.StatusCode
+		result.Error = appErr.Message
+	}
+
+	return result
+}
+
+func importErrorResult(result models.NewsImportResult, err error) models.NewsImportResult {
+	result.Id = 0
+	result.Success = false
+	result.Error = "Internal server error"
+
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		result.Error = appErr.Message
+	}
+
+	return result
+}
+
+// rollbackBatchResults помечает выполненные и невыполненные операции откатившегося пакета
+func rollbackBatchResults(results []models.NewsBatchResult, items []models.NewsBatchItem) []models.NewsBatchResult {
+	failed := len(results) - 1
+	for i := range results[:failed] {
+		results[i].Success = false
+		results[i].Status = 409
+		results[i].Error = "rolled back"
+		if results[i].Op == models.BatchOpCreate {
+			results[i].Id = 0
+		}
+	}
+
+	for _, item := range items[len(results):] {
+		results = append(results, models.NewsBatchResult{
+			Index:  item.Index,
+			Op:     item.Op,
+			Id:     item.NewsID,
+			Status: 409,
+			Error:  "not executed",
+		})
+	}
+
+	return results
+}
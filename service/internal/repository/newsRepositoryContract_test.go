@@ -0,0 +1,820 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"service/internal/apperrors"
+	"service/internal/content"
+	"service/internal/models"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runNewsRepositoryContract проверяет, что реализация INewsRepository ведёт себя как NewsRepository.
+// newRepository должен возвращать пустое хранилище для каждого подтеста.
+func runNewsRepositoryContract(t *testing.T, newRepository func(t *testing.T) INewsRepository) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		id := createNews(t, repo, "title", "content", []int64{3, 1, 3})
+
+		n, err := repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, n.ID)
+		assert.Equal(t, "title", n.Title)
+		assert.Equal(t, "content", n.Content)
+		assert.Equal(t, int64(1), n.Version)
+		assert.False(t, n.UpdatedAt.IsZero())
+		assert.Nil(t, n.ExternalID)
+		assert.Equal(t, []int64{1, 3}, sorted(n.Categories))
+	})
+
+	t.Run("GetNewsByIDNotFound", func(t *testing.T) {
+		repo := newRepository(t)
+
+		_, err := repo.GetNewsByID(context.Background(), 42)
+		assertNotFound(t, err)
+	})
+
+	t.Run("EmptyCategories", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		withoutCategories, err := repo.CreateNews(ctx, models.NewsCreateForm{Title: "a", Content: "a"})
+		require.NoError(t, err)
+		withEmptyCategories := createNews(t, repo, "b", "b", []int64{})
+
+		for _, id := range []int64{withoutCategories, withEmptyCategories} {
+			n, err := repo.GetNewsByID(ctx, id)
+			require.NoError(t, err)
+			assert.NotNil(t, n.Categories, "news %d", id)
+			assert.Empty(t, n.Categories, "news %d", id)
+		}
+
+		newsList, err := repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		require.Len(t, newsList, 2)
+		for _, n := range newsList {
+			assert.NotNil(t, n.Categories, "news %d", n.ID)
+			assert.Empty(t, n.Categories, "news %d", n.ID)
+		}
+
+		exported := exportAll(t, repo)
+		require.Len(t, exported, 2)
+		for _, n := range exported {
+			assert.NotNil(t, n.Categories, "news %d", n.ID)
+		}
+	})
+
+	t.Run("Ordering", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		var ids []int64
+		for i := 0; i < 5; i++ {
+			ids = append(ids, createNews(t, repo, fmt.Sprintf("news %d", i), "content", nil))
+		}
+		require.True(t, slices.IsSorted(ids), "ids must grow: %v", ids)
+
+		newsList, err := repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, reversed(ids), newsIDs(newsList), "list is ordered by id descending")
+
+		assert.Equal(t, ids, newsIDs(exportAll(t, repo)), "export is ordered by id ascending")
+
+		// Изменение новости не меняет её место в списке
+		title := "updated"
+		require.NoError(t, repo.UpdateNews(ctx, ids[0], map[string]interface{}{"title": &title}, models.CategoryChanges{}))
+		newsList, err = repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, reversed(ids), newsIDs(newsList))
+	})
+
+	t.Run("PaginationBounds", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		var ids []int64
+		for i := 0; i < 5; i++ {
+			ids = append(ids, createNews(t, repo, fmt.Sprintf("news %d", i), "content", nil))
+		}
+		desc := reversed(ids)
+
+		tests := []struct {
+			name          string
+			limit, offset int64
+			want          []int64
+		}{
+			{name: "first page", limit: 2, offset: 0, want: desc[:2]},
+			{name: "middle page", limit: 2, offset: 2, want: desc[2:4]},
+			{name: "last partial page", limit: 2, offset: 4, want: desc[4:]},
+			{name: "limit above total", limit: 100, offset: 0, want: desc},
+			{name: "offset at total", limit: 2, offset: 5, want: nil},
+			{name: "offset above total", limit: 2, offset: 100, want: nil},
+			{name: "zero limit", limit: 0, offset: 0, want: nil},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				newsList, err := repo.GetNews(ctx, tt.limit, tt.offset, models.NewsFilter{})
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, newsIDs(newsList))
+			})
+		}
+
+		_, err := repo.GetNews(ctx, -1, 0, models.NewsFilter{})
+		assert.Error(t, err, "negative limit")
+		_, err = repo.GetNews(ctx, 10, -1, models.NewsFilter{})
+		assert.Error(t, err, "negative offset")
+	})
+
+	t.Run("UpdateFields", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		id := createNews(t, repo, "title", "content", []int64{1})
+		before, err := repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+
+		title := "new title"
+		require.NoError(t, repo.UpdateNews(ctx, id, map[string]interface{}{"title": &title}, models.CategoryChanges{}))
+
+		n, err := repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "new title", n.Title)
+		assert.Equal(t, "content", n.Content, "content is not touched")
+		assert.Equal(t, []int64{1}, n.Categories, "categories are not touched")
+		assert.Equal(t, int64(2), n.Version)
+		assert.False(t, n.UpdatedAt.Before(before.UpdatedAt))
+
+		content := "new content"
+		require.NoError(t, repo.UpdateNews(ctx, id, map[string]interface{}{"content": &content}, models.CategoryChanges{}))
+
+		n, err = repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "new title", n.Title)
+		assert.Equal(t, "new content", n.Content)
+		assert.Equal(t, int64(3), n.Version)
+	})
+
+	t.Run("ContentFormat", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		id := createNews(t, repo, "title", "a < b", nil)
+		n, err := repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, content.FormatPlain, n.ContentFormat, "plain by default")
+		assert.Equal(t, "<p>a &lt; b</p>", n.ContentHTML)
+
+		id, err = repo.CreateNews(ctx, models.NewsCreateForm{Title: "md", Content: "**bold**", ContentFormat: content.FormatMarkdown})
+		require.NoError(t, err)
+		n, err = repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "**bold**", n.Content, "source is stored as is")
+		assert.Equal(t, "<p><strong>bold</strong></p>\n", n.ContentHTML)
+
+		source := "<b onclick=\"x()\">bold</b>"
+		require.NoError(t, repo.UpdateNews(ctx, id, map[string]interface{}{"content": &source}, models.CategoryChanges{}))
+		n, err = repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, content.FormatMarkdown, n.ContentFormat, "format is kept")
+		assert.Equal(t, "<p><b>bold</b></p>\n", n.ContentHTML)
+
+		format := content.FormatPlain
+		require.NoError(t, repo.UpdateNews(ctx, id, map[string]interface{}{"content_format": &format}, models.CategoryChanges{}))
+		n, err = repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, content.FormatPlain, n.ContentFormat)
+		assert.Equal(t, "<p>&lt;b onclick=\"x()\"&gt;bold&lt;/b&gt;</p>", n.ContentHTML, "format change renders again")
+	})
+
+	t.Run("CategoryChanges", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			initial []int64
+			changes models.CategoryChanges
+			want    []int64
+		}{
+			{name: "replace", initial: []int64{1, 2}, changes: models.CategoryChanges{Replace: &[]int64{2, 3}}, want: []int64{2, 3}},
+			{name: "replace with duplicates", initial: []int64{1}, changes: models.CategoryChanges{Replace: &[]int64{4, 4, 5}}, want: []int64{4, 5}},
+			{name: "replace with empty", initial: []int64{1, 2}, changes: models.CategoryChanges{Replace: &[]int64{}}, want: []int64{}},
+			{name: "replace empty news", initial: nil, changes: models.CategoryChanges{Replace: &[]int64{7}}, want: []int64{7}},
+			{name: "add", initial: []int64{1}, changes: models.CategoryChanges{Add: []int64{2, 1, 2}}, want: []int64{1, 2}},
+			{name: "remove", initial: []int64{1, 2, 3}, changes: models.CategoryChanges{Remove: []int64{2, 9}}, want: []int64{1, 3}},
+			{name: "remove all", initial: []int64{1}, changes: models.CategoryChanges{Remove: []int64{1}}, want: []int64{}},
+			{name: "add and remove", initial: []int64{1, 2}, changes: models.CategoryChanges{Add: []int64{3}, Remove: []int64{1}}, want: []int64{2, 3}},
+			{name: "replace then add", initial: []int64{1}, changes: models.CategoryChanges{Replace: &[]int64{2}, Add: []int64{3}}, want: []int64{2, 3}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := newRepository(t)
+				ctx := context.Background()
+
+				id := createNews(t, repo, "title", "content", tt.initial)
+				require.NoError(t, repo.UpdateNews(ctx, id, map[string]interface{}{}, tt.changes))
+
+				n, err := repo.GetNewsByID(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, sorted(n.Categories))
+				assert.Equal(t, "title", n.Title)
+				assert.Equal(t, int64(2), n.Version, "category changes bump version")
+			})
+		}
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		untagged := createNews(t, repo, "untagged", "content", nil)
+		elections, err := repo.CreateNews(ctx, models.NewsCreateForm{Title: "a", Content: "a", Tags: []string{"выборы-2026", "politics"}})
+		require.NoError(t, err)
+		economy, err := repo.CreateNews(ctx, models.NewsCreateForm{Title: "b", Content: "b", Tags: []string{"politics", "economy"}})
+		require.NoError(t, err)
+
+		n, err := repo.GetNewsByID(ctx, untagged)
+		require.NoError(t, err)
+		assert.NotNil(t, n.Tags)
+		assert.Empty(t, n.Tags)
+
+		n, err = repo.GetNewsByID(ctx, elections)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"politics", "выборы-2026"}, n.Tags, "tags are sorted")
+
+		for name, tt := range map[string]struct {
+			tags []string
+			want []int64
+		}{
+			"no filter":           {want: []int64{economy, elections, untagged}},
+			"one tag":             {tags: []string{"politics"}, want: []int64{economy, elections}},
+			"all tags must match": {tags: []string{"politics", "economy"}, want: []int64{economy}},
+			"unknown tag":         {tags: []string{"sports"}, want: nil},
+		} {
+			newsList, err := repo.GetNews(ctx, 10, 0, models.NewsFilter{Tags: tt.tags})
+			require.NoError(t, err, name)
+			assert.Equal(t, tt.want, newsIDs(newsList), name)
+		}
+
+		newsList, err := repo.GetNews(ctx, 1, 1, models.NewsFilter{Tags: []string{"politics"}})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{elections}, newsIDs(newsList), "pagination applies to filtered list")
+
+		tags, err := repo.GetTags(ctx, "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []models.TagCount{{Name: "politics", Count: 2}, {Name: "economy", Count: 1}, {Name: "выборы-2026", Count: 1}}, tags)
+
+		tags, err = repo.GetTags(ctx, "выб", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []models.TagCount{{Name: "выборы-2026", Count: 1}}, tags)
+
+		tags, err = repo.GetTags(ctx, "", 1)
+		require.NoError(t, err)
+		assert.Len(t, tags, 1)
+
+		// Замена тегов создаёт недостающие и снимает лишние, неиспользуемые теги не подсказываются
+		replaced := []string{"economy", "markets"}
+		require.NoError(t, repo.UpdateNews(ctx, elections, map[string]interface{}{"tags": &replaced}, models.CategoryChanges{}))
+		n, err = repo.GetNewsByID(ctx, elections)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"economy", "markets"}, n.Tags)
+		assert.Equal(t, int64(2), n.Version)
+
+		tags, err = repo.GetTags(ctx, "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []models.TagCount{{Name: "economy", Count: 2}, {Name: "markets", Count: 1}, {Name: "politics", Count: 1}}, tags)
+
+		// Спецсимволы LIKE в префиксе ищутся буквально
+		tags, err = repo.GetTags(ctx, "%", 10)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+
+		cleared := []string{}
+		require.NoError(t, repo.UpdateNews(ctx, elections, map[string]interface{}{"tags": &cleared}, models.CategoryChanges{}))
+		n, err = repo.GetNewsByID(ctx, elections)
+		require.NoError(t, err)
+		assert.Empty(t, n.Tags)
+
+		// Изменение без тегов их не трогает, удаление новости снимает её теги
+		title := "new title"
+		require.NoError(t, repo.UpdateNews(ctx, economy, map[string]interface{}{"title": &title}, models.CategoryChanges{}))
+		n, err = repo.GetNewsByID(ctx, economy)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"economy", "politics"}, n.Tags)
+
+		_, err = repo.DeleteNews(ctx, economy)
+		require.NoError(t, err)
+		tags, err = repo.GetTags(ctx, "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("UpdateNewsNotFound", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		title := "title"
+		err := repo.UpdateNews(ctx, 42, map[string]interface{}{"title": &title}, models.CategoryChanges{Add: []int64{1}})
+		assertNotFound(t, err)
+
+		newsList, err := repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, newsList, "failed update must not create news")
+	})
+
+	t.Run("DeleteNews", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		kept := createNews(t, repo, "kept", "content", []int64{1})
+		deleted := createNews(t, repo, "deleted", "content", []int64{1, 2})
+
+		_, err := repo.DeleteNews(ctx, deleted)
+		require.NoError(t, err)
+
+		_, err = repo.GetNewsByID(ctx, deleted)
+		assertNotFound(t, err)
+		_, err = repo.DeleteNews(ctx, deleted)
+		assertNotFound(t, err)
+
+		newsList, err := repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{kept}, newsIDs(newsList))
+
+		n, err := repo.GetNewsByID(ctx, kept)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, n.Categories)
+	})
+
+	t.Run("Slugs", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		first := createNews(t, repo, "Новости дня", "content", nil)
+		second := createNews(t, repo, "Новости  дня!", "content", nil)
+		third := createNews(t, repo, "новости дня", "content", nil)
+		untitled := createNews(t, repo, "???", "content", nil)
+
+		for id, want := range map[int64]string{first: "novosti-dnya", second: "novosti-dnya-2", third: "novosti-dnya-3", untitled: "news"} {
+			n, err := repo.GetNewsByID(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, want, n.Slug, "news %d", id)
+
+			n, err = repo.GetNewsBySlug(ctx, want)
+			require.NoError(t, err)
+			assert.Equal(t, id, n.ID, "slug %s", want)
+		}
+
+		// Заголовок с той же основой slug не меняет
+		title := "Новости дня."
+		require.NoError(t, repo.UpdateNews(ctx, second, map[string]interface{}{"title": &title}, models.CategoryChanges{}))
+		n, err := repo.GetNewsByID(ctx, second)
+		require.NoError(t, err)
+		assert.Equal(t, "novosti-dnya-2", n.Slug)
+
+		// Новый заголовок - новый slug, прежний ведёт на ту же новость
+		title = "Главная новость"
+		require.NoError(t, repo.UpdateNews(ctx, first, map[string]interface{}{"title": &title}, models.CategoryChanges{}))
+		n, err = repo.GetNewsBySlug(ctx, "novosti-dnya")
+		require.NoError(t, err)
+		assert.Equal(t, first, n.ID)
+		assert.Equal(t, "glavnaya-novost", n.Slug)
+
+		// Прежний slug занят историей и другой новости не достаётся
+		another := createNews(t, repo, "Новости дня", "content", nil)
+		n, err = repo.GetNewsByID(ctx, another)
+		require.NoError(t, err)
+		assert.Equal(t, "novosti-dnya-4", n.Slug)
+
+		// Возврат к прежнему заголовку возвращает прежний slug
+		title = "Новости дня"
+		require.NoError(t, repo.UpdateNews(ctx, first, map[string]interface{}{"title": &title}, models.CategoryChanges{}))
+		n, err = repo.GetNewsBySlug(ctx, "glavnaya-novost")
+		require.NoError(t, err)
+		assert.Equal(t, "novosti-dnya", n.Slug)
+
+		// Число в конце заголовка - часть основы, а не суффикс от совпадения
+		results := createNews(t, repo, "Итоги 2025", "content", nil)
+		n, err = repo.GetNewsByID(ctx, results)
+		require.NoError(t, err)
+		assert.Equal(t, "itogi-2025", n.Slug)
+
+		title = "Итоги"
+		require.NoError(t, repo.UpdateNews(ctx, results, map[string]interface{}{"title": &title}, models.CategoryChanges{}))
+		n, err = repo.GetNewsBySlug(ctx, "itogi-2025")
+		require.NoError(t, err)
+		assert.Equal(t, "itogi", n.Slug)
+
+		_, err = repo.GetNewsBySlug(ctx, "unknown")
+		assertNotFound(t, err)
+
+		// Удаление освобождает все slug новости
+		_, err = repo.DeleteNews(ctx, first)
+		require.NoError(t, err)
+		for _, slug := range []string{"novosti-dnya", "glavnaya-novost"} {
+			_, err = repo.GetNewsBySlug(ctx, slug)
+			assertNotFound(t, err)
+		}
+		n, err = repo.GetNewsByID(ctx, createNews(t, repo, "Главная новость", "content", nil))
+		require.NoError(t, err)
+		assert.Equal(t, "glavnaya-novost", n.Slug)
+	})
+
+	t.Run("Translations", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		first := createNews(t, repo, "Новость", "текст", nil)
+		second := createNews(t, repo, "Другая", "текст", nil)
+
+		before, err := repo.GetNewsByID(ctx, first)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.CreateTranslation(ctx, first, "en", models.NewsTranslationCreateForm{
+			Title: "News", Content: "**text**", ContentFormat: content.FormatMarkdown,
+		}))
+		require.NoError(t, repo.CreateTranslation(ctx, first, "de", models.NewsTranslationCreateForm{Title: "Nachricht", Content: "Text"}))
+
+		// Перевод - часть новости: версия и время изменения растут
+		after, err := repo.GetNewsByID(ctx, first)
+		require.NoError(t, err)
+		assert.Equal(t, before.Version+2, after.Version)
+		assert.False(t, after.UpdatedAt.Before(before.UpdatedAt))
+
+		err = repo.CreateTranslation(ctx, first, "en", models.NewsTranslationCreateForm{Title: "Again", Content: "text"})
+		var appErr *apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 409, appErr.StatusCode)
+
+		err = repo.CreateTranslation(ctx, 999999, "en", models.NewsTranslationCreateForm{Title: "News", Content: "text"})
+		assertNotFound(t, err)
+
+		translations, err := repo.GetTranslations(ctx, []int64{first, second}, []string{"en", "fr"})
+		require.NoError(t, err)
+		require.Len(t, translations, 1)
+		assert.Equal(t, first, translations[0].NewsID)
+		assert.Equal(t, "en", translations[0].Locale)
+		assert.Equal(t, "News", translations[0].Title)
+		assert.Equal(t, content.FormatMarkdown, translations[0].ContentFormat)
+		assert.Equal(t, "<p><strong>text</strong></p>\n", translations[0].ContentHTML)
+
+		title, format := "News!", content.FormatPlain
+		require.NoError(t, repo.UpdateTranslation(ctx, first, "en", map[string]interface{}{"title": &title, "content_format": &format}))
+		translations, err = repo.GetTranslations(ctx, []int64{first}, []string{"en"})
+		require.NoError(t, err)
+		require.Len(t, translations, 1)
+		assert.Equal(t, "News!", translations[0].Title)
+		assert.Equal(t, "<p>**text**</p>", translations[0].ContentHTML, "html follows the new format")
+
+		err = repo.UpdateTranslation(ctx, first, "fr", map[string]interface{}{"title": &title})
+		assertNotFound(t, err)
+		err = repo.UpdateTranslation(ctx, 999999, "en", map[string]interface{}{"title": &title})
+		assertNotFound(t, err)
+
+		// Удаление новости удаляет и переводы
+		_, err = repo.DeleteNews(ctx, first)
+		require.NoError(t, err)
+		translations, err = repo.GetTranslations(ctx, []int64{first}, []string{"en", "de"})
+		require.NoError(t, err)
+		assert.Empty(t, translations)
+	})
+
+	t.Run("Media", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		first := createNews(t, repo, "first", "content", nil)
+		second := createNews(t, repo, "second", "content", nil)
+
+		before, err := repo.GetNewsByID(ctx, first)
+		require.NoError(t, err)
+
+		photo := models.Media{NewsID: first, StorageKey: "aa/photo", MimeType: "image/png", Size: 10, Checksum: checksum("photo"), AltText: "Фото"}
+		require.NoError(t, repo.CreateMedia(ctx, &photo))
+		assert.NotZero(t, photo.ID)
+		assert.False(t, photo.CreatedAt.IsZero())
+		chart := models.Media{NewsID: first, StorageKey: "bb/chart", MimeType: "image/gif", Size: 20, Checksum: checksum("chart")}
+		require.NoError(t, repo.CreateMedia(ctx, &chart))
+		other := models.Media{NewsID: second, StorageKey: "cc/other", MimeType: "image/jpeg", Size: 30, Checksum: checksum("other")}
+		require.NoError(t, repo.CreateMedia(ctx, &other))
+
+		err = repo.CreateMedia(ctx, &models.Media{NewsID: 999999, StorageKey: "dd/missing", MimeType: "image/png", Checksum: checksum("missing")})
+		assertNotFound(t, err)
+
+		// Вложение - часть новости: версия растёт, вложения идут в порядке загрузки
+		after, err := repo.GetNewsByID(ctx, first)
+		require.NoError(t, err)
+		assert.Equal(t, before.Version+2, after.Version)
+		require.Len(t, after.Media, 2)
+		assert.Equal(t, []int64{photo.ID, chart.ID}, []int64{after.Media[0].ID, after.Media[1].ID})
+		assert.Equal(t, "Фото", after.Media[0].AltText)
+		assert.Equal(t, "aa/photo", after.Media[0].StorageKey)
+		assert.WithinDuration(t, photo.CreatedAt, after.Media[0].CreatedAt, time.Millisecond)
+
+		newsList, err := repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		require.Len(t, newsList, 2)
+		assert.Len(t, newsList[0].Media, 1, "second news")
+		assert.Len(t, newsList[1].Media, 2, "first news")
+
+		got, err := repo.GetMedia(ctx, photo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, photo.NewsID, got.NewsID)
+		assert.Equal(t, photo.MimeType, got.MimeType)
+		assert.Equal(t, photo.Size, got.Size)
+		assert.Equal(t, photo.Checksum, got.Checksum)
+		assert.Equal(t, photo.StorageKey, got.StorageKey)
+		assert.WithinDuration(t, photo.CreatedAt, got.CreatedAt, time.Second)
+
+		_, err = repo.GetMedia(ctx, 999999)
+		assertNotFound(t, err)
+
+		// Удаление новости возвращает её вложения, чтобы их файлы можно было удалить
+		deleted, err := repo.DeleteNews(ctx, first)
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+		assert.ElementsMatch(t, []string{"aa/photo", "bb/chart"}, []string{deleted[0].StorageKey, deleted[1].StorageKey})
+		_, err = repo.GetMedia(ctx, photo.ID)
+		assertNotFound(t, err)
+
+		deleted, err = repo.DeleteNews(ctx, createNews(t, repo, "without media", "content", nil))
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+	})
+
+	t.Run("ApplyBatch", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		existing := createNews(t, repo, "existing", "content", nil)
+		title := "edited"
+		items := []models.NewsBatchItem{
+			{Index: 0, Op: models.BatchOpCreate, Create: &models.NewsCreateForm{Title: "created", Content: "content"}},
+			{Index: 1, Op: models.BatchOpEdit, NewsID: existing, Edit: &models.NewsEditForm{Title: &title}},
+			{Index: 2, Op: models.BatchOpEdit, NewsID: existing + 100, Edit: &models.NewsEditForm{Title: &title}},
+			{Index: 3, Op: models.BatchOpCreate, Create: &models.NewsCreateForm{Title: "not executed", Content: "content"}},
+		}
+
+		results, err := repo.ApplyBatch(ctx, items, true)
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.Equal(t, []int{409, 409, 404, 409}, batchStatuses(results))
+		for _, result := range results {
+			assert.False(t, result.Success)
+		}
+
+		newsList, err := repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{existing}, newsIDs(newsList), "atomic batch is rolled back")
+		assert.Equal(t, "existing", newsList[0].Title)
+		assert.Equal(t, int64(1), newsList[0].Version)
+
+		results, err = repo.ApplyBatch(ctx, items, false)
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.Equal(t, []int{201, 200, 404, 201}, batchStatuses(results))
+
+		n, err := repo.GetNewsByID(ctx, existing)
+		require.NoError(t, err)
+		assert.Equal(t, "edited", n.Title)
+
+		newsList, err = repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		assert.Len(t, newsList, 3)
+	})
+
+	t.Run("ImportNews", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		items := []models.NewsImportItem{
+			{Line: 1, ExternalID: "ext-1", Form: models.NewsCreateForm{Title: "first", Content: "content", Categories: &[]int64{1}}},
+			{Line: 2, Form: models.NewsCreateForm{Title: "no external id", Content: "content"}},
+		}
+
+		planned, err := repo.ImportNews(ctx, items, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{models.ImportActionCreate, models.ImportActionCreate}, importActions(planned))
+		newsList, err := repo.GetNews(ctx, 10, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, newsList, "dry run must not write")
+
+		results, err := repo.ImportNews(ctx, items, false)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.True(t, result.Success, "line %d: %s", result.Line, result.Error)
+		}
+		created := results[0].Id
+
+		n, err := repo.GetNewsByID(ctx, created)
+		require.NoError(t, err)
+		require.NotNil(t, n.ExternalID)
+		assert.Equal(t, "ext-1", *n.ExternalID)
+
+		update := []models.NewsImportItem{
+			{Line: 1, ExternalID: "ext-1", Form: models.NewsCreateForm{Title: "second", Content: "new content", Categories: &[]int64{2, 3}}},
+		}
+
+		planned, err = repo.ImportNews(ctx, update, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{models.ImportActionUpdate}, importActions(planned))
+
+		results, err = repo.ImportNews(ctx, update, false)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Success)
+		assert.Equal(t, models.ImportActionUpdate, results[0].Action)
+		assert.Equal(t, created, results[0].Id)
+
+		n, err = repo.GetNewsByID(ctx, created)
+		require.NoError(t, err)
+		assert.Equal(t, "second", n.Title)
+		assert.Equal(t, "new content", n.Content)
+		assert.Equal(t, []int64{2, 3}, sorted(n.Categories))
+		assert.Equal(t, int64(2), n.Version)
+	})
+
+	t.Run("ExportNewsStopsOnError", func(t *testing.T) {
+		repo := newRepository(t)
+
+		createNews(t, repo, "first", "content", nil)
+		createNews(t, repo, "second", "content", nil)
+
+		errStop := errors.New("stop")
+		calls := 0
+		err := repo.ExportNews(context.Background(), func(models.NewsWithCategories) error {
+			calls++
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("ConcurrentUpdates", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		const workers = 20
+		id := createNews(t, repo, "title", "content", nil)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, workers)
+		for i := 1; i <= workers; i++ {
+			wg.Add(1)
+			go func(category int64) {
+				defer wg.Done()
+				title := fmt.Sprintf("title %d", category)
+				errs <- repo.UpdateNews(ctx, id, map[string]interface{}{"title": &title}, models.CategoryChanges{Add: []int64{category}})
+			}(int64(i))
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		n, err := repo.GetNewsByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, int64(workers+1), n.Version, "every update bumps version, none is lost")
+
+		want := make([]int64, 0, workers)
+		for i := int64(1); i <= workers; i++ {
+			want = append(want, i)
+		}
+		assert.Equal(t, want, sorted(n.Categories))
+		assert.Regexp(t, `^title \d+$`, n.Title)
+	})
+
+	t.Run("ConcurrentCreates", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		const workers = 20
+		var wg sync.WaitGroup
+		ids := make(chan int64, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				id, err := repo.CreateNews(ctx, models.NewsCreateForm{Title: "title", Content: "content"})
+				assert.NoError(t, err)
+				ids <- id
+			}()
+		}
+		wg.Wait()
+		close(ids)
+
+		seen := make(map[int64]struct{}, workers)
+		for id := range ids {
+			seen[id] = struct{}{}
+		}
+		assert.Len(t, seen, workers, "ids are unique")
+
+		newsList, err := repo.GetNews(ctx, 100, 0, models.NewsFilter{})
+		require.NoError(t, err)
+		assert.Len(t, newsList, workers)
+
+		slugs := make(map[string]struct{}, workers)
+		for _, n := range newsList {
+			slugs[n.Slug] = struct{}{}
+		}
+		assert.Len(t, slugs, workers, "slugs are unique")
+	})
+}
+
+func createNews(t *testing.T, repo INewsRepository, title, content string, categories []int64) int64 {
+	t.Helper()
+
+	form := models.NewsCreateForm{Title: title, Content: content}
+	if categories != nil {
+		form.Categories = &categories
+	}
+
+	id, err := repo.CreateNews(context.Background(), form)
+	require.NoError(t, err)
+	require.NotZero(t, id)
+
+	return id
+}
+
+func exportAll(t *testing.T, repo INewsRepository) []models.NewsWithCategories {
+	t.Helper()
+
+	var newsList []models.NewsWithCategories
+	require.NoError(t, repo.ExportNews(context.Background(), func(n models.NewsWithCategories) error {
+		newsList = append(newsList, n)
+		return nil
+	}))
+
+	return newsList
+}
+
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, apperrors.ErrNewsNotFound)
+
+	var appErr *apperrors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, 404, appErr.StatusCode)
+	}
+}
+
+// checksum - SHA-256 в hex, как его считает сервис при загрузке
+func checksum(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func newsIDs(newsList []models.NewsWithCategories) []int64 {
+	var ids []int64
+	for _, n := range newsList {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func batchStatuses(results []models.NewsBatchResult) []int {
+	statuses := make([]int, 0, len(results))
+	for _, result := range results {
+		statuses = append(statuses, result.Status)
+	}
+	return statuses
+}
+
+func importActions(results []models.NewsImportResult) []string {
+	actions := make([]string, 0, len(results))
+	for _, result := range results {
+		actions = append(actions, result.Action)
+	}
+	return actions
+}
+
+// sorted - порядок категорий в ответе не входит в контракт, сравниваем отсортированными
+func sorted(ids []int64) []int64 {
+	if ids == nil {
+		return nil
+	}
+	result := slices.Clone(ids)
+	slices.Sort(result)
+	return result
+}
+
+func reversed(ids []int64) []int64 {
+	result := slices.Clone(ids)
+	slices.Reverse(result)
+	return result
+}
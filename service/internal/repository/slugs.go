@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"service/pkg/slug"
+	"strconv"
+)
+
+// defaultSlug - основа slug для заголовка без букв и цифр
+const defaultSlug = "news"
+
+func slugBase(title string) string {
+	if base := slug.Make(title); base != "" {
+		return base
+	}
+	return defaultSlug
+}
+
+// slugCandidate возвращает первый из base, base-2, base-3..., который свободен или уже принадлежит новости,
+// и признак того, что он уже принадлежит новости. taken - занятые slug с этой основой и их новости.
+// Так текущий base-N остаётся у новости, только пока base..base-(N-1) заняты другими:
+// число в конце заголовка ("Итоги 2025") суффиксом не считается.
+func slugCandidate(base string, taken map[string]int64, newsId int64) (string, bool) {
+	candidate := base
+	for n := 2; ; n++ {
+		owner, ok := taken[candidate]
+		if !ok {
+			return candidate, false
+		}
+		if owner == newsId {
+			return candidate, true
+		}
+		candidate = base + "-" + strconv.Itoa(n)
+	}
+}
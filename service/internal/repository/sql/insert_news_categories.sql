@@ -1 +0,0 @@
-INSERT INTO news_categories (news_id, category_id) VALUES ($1, $2)
\ No newline at end of file
@@ -0,0 +1,4 @@
+INSERT INTO news_categories (news_id, category_id)
+SELECT DISTINCT $1::bigint, added.category_id
+FROM unnest($2::bigint[]) AS added(category_id)
+ON CONFLICT (news_id, category_id) DO NOTHING
\ No newline at end of file
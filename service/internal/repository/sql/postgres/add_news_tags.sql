@@ -0,0 +1,5 @@
+INSERT INTO news_tags (news_id, tag_id)
+SELECT $1::bigint, id
+FROM tags
+WHERE name = ANY ($2::text[])
+ON CONFLICT (news_id, tag_id) DO NOTHING
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM news_tags WHERE news_id = $1
\ No newline at end of file
@@ -0,0 +1,3 @@
+DELETE FROM news_tags
+WHERE news_id = $1
+  AND tag_id NOT IN (SELECT id FROM tags WHERE name = ANY ($2::text[]))
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM idempotency_keys WHERE client = $1 AND key = $2 AND lock_token = $3 AND status_code IS NULL
\ No newline at end of file
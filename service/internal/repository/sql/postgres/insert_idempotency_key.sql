@@ -0,0 +1,3 @@
+INSERT INTO idempotency_keys (client, key, request_hash, lock_token, locked_until, expires_at)
+VALUES ($1, $2, $3, $4, now() + make_interval(secs => $5), now() + make_interval(secs => $6))
+ON CONFLICT (client, key) DO NOTHING
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO media (news_id, storage_key, mime_type, size, checksum, alt_text, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO news_slugs (slug, news_id)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO NOTHING
\ No newline at end of file
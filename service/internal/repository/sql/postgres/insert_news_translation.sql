@@ -0,0 +1,3 @@
+INSERT INTO news_translations (news_id, locale, title, content, content_format, content_html, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (news_id, locale) DO NOTHING
\ No newline at end of file
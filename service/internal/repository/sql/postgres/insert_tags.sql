@@ -0,0 +1,4 @@
+INSERT INTO tags (name)
+SELECT DISTINCT added.name
+FROM unnest($1::text[]) AS added(name)
+ON CONFLICT (name) DO NOTHING
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM news_categories WHERE news_id = $1 AND category_id = ANY ($2::bigint[])
\ No newline at end of file
@@ -0,0 +1,9 @@
+WITH removed AS (
+    DELETE FROM news_categories
+    WHERE news_id = $1
+      AND NOT (category_id = ANY ($2::bigint[]))
+)
+INSERT INTO news_categories (news_id, category_id)
+SELECT DISTINCT $1::bigint, wanted.category_id
+FROM unnest($2::bigint[]) AS wanted(category_id)
+ON CONFLICT (news_id, category_id) DO NOTHING
\ No newline at end of file
@@ -0,0 +1,3 @@
+SELECT external_id
+FROM news
+WHERE external_id = ANY($1::varchar[]);
\ No newline at end of file
@@ -0,0 +1,31 @@
+SELECT id,
+       title,
+       content,
+       version,
+       updated_at,
+       external_id,
+       content_format,
+       content_html,
+       slug,
+       COALESCE((SELECT json_agg(nc.category_id ORDER BY nc.category_id)
+                 FROM news_categories nc
+                 WHERE nc.news_id = news.id), '[]') AS categories,
+       COALESCE((SELECT json_agg(t.name ORDER BY t.name COLLATE "C")
+                 FROM news_tags nt
+                          JOIN tags t ON t.id = nt.tag_id
+                 WHERE nt.news_id = news.id), '[]') AS tags,
+       COALESCE((SELECT json_agg(json_build_object('ID', m.id, 'NewsID', m.news_id, 'MimeType', m.mime_type,
+                                                   'Size', m.size, 'Checksum', m.checksum, 'AltText', m.alt_text,
+                                                   'CreatedAt', m.created_at, 'StorageKey', m.storage_key)
+                                 ORDER BY m.id)
+                 FROM media m
+                 WHERE m.news_id = news.id), '[]') AS media
+FROM news
+WHERE id IN (SELECT nt.news_id
+             FROM news_tags nt
+                      JOIN tags t ON t.id = nt.tag_id
+             WHERE t.name = ANY ($3::text[])
+             GROUP BY nt.news_id
+             HAVING count(*) = $4)
+ORDER BY id DESC
+    LIMIT $1 OFFSET $2;
\ No newline at end of file
@@ -0,0 +1,4 @@
+SELECT news_id, locale, title, content, content_format, content_html, updated_at
+FROM news_translations
+WHERE news_id = $1
+  AND locale = $2;
\ No newline at end of file
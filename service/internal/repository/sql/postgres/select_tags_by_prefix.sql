@@ -0,0 +1,7 @@
+SELECT t.name, count(*) AS news_count
+FROM tags t
+         JOIN news_tags nt ON nt.tag_id = t.id
+WHERE t.name LIKE $1 ESCAPE '\'
+GROUP BY t.name
+ORDER BY news_count DESC, t.name COLLATE "C"
+    LIMIT $2;
\ No newline at end of file
@@ -0,0 +1,8 @@
+UPDATE idempotency_keys
+SET request_hash = $3,
+    lock_token   = $4,
+    locked_until = now() + make_interval(secs => $5)
+WHERE client = $1
+  AND key = $2
+  AND status_code IS NULL
+  AND locked_until < now()
\ No newline at end of file
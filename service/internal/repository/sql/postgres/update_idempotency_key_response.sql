@@ -0,0 +1,9 @@
+UPDATE idempotency_keys
+SET status_code   = $4,
+    content_type  = $5,
+    response_body = $6,
+    lock_token    = NULL,
+    locked_until  = NULL
+WHERE client = $1
+  AND key = $2
+  AND lock_token = $3
\ No newline at end of file
@@ -0,0 +1,5 @@
+INSERT INTO news_categories (news_id, category_id)
+SELECT DISTINCT ?1, added.value
+FROM json_each(?2) AS added
+WHERE true
+ON CONFLICT (news_id, category_id) DO NOTHING
\ No newline at end of file
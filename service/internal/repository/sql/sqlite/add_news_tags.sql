@@ -0,0 +1,5 @@
+INSERT INTO news_tags (news_id, tag_id)
+SELECT ?1, id
+FROM tags
+WHERE name IN (SELECT value FROM json_each(?2))
+ON CONFLICT (news_id, tag_id) DO NOTHING
\ No newline at end of file
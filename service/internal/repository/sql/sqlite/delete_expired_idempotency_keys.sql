@@ -0,0 +1 @@
+DELETE FROM idempotency_keys WHERE expires_at < strftime('%Y-%m-%d %H:%M:%f', 'now')
\ No newline at end of file
@@ -0,0 +1,2 @@
+DELETE FROM media WHERE news_id = ?1
+RETURNING id, news_id, mime_type, size, checksum, alt_text, created_at, storage_key
\ No newline at end of file
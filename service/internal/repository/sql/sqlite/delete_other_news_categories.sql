@@ -0,0 +1,3 @@
+DELETE FROM news_categories
+WHERE news_id = ?1
+  AND category_id NOT IN (SELECT value FROM json_each(?2))
\ No newline at end of file
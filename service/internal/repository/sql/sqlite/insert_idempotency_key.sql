@@ -0,0 +1,4 @@
+INSERT INTO idempotency_keys (client, key, request_hash, lock_token, locked_until, expires_at)
+VALUES (?1, ?2, ?3, ?4, strftime('%Y-%m-%d %H:%M:%f', 'now', '+' || ?5 || ' seconds'),
+        strftime('%Y-%m-%d %H:%M:%f', 'now', '+' || ?6 || ' seconds'))
+ON CONFLICT (client, key) DO NOTHING
\ No newline at end of file
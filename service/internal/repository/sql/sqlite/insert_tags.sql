@@ -0,0 +1,5 @@
+INSERT INTO tags (name)
+SELECT DISTINCT added.value
+FROM json_each(?1) AS added
+WHERE true
+ON CONFLICT (name) DO NOTHING
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM news_categories WHERE news_id = ?1 AND category_id IN (SELECT value FROM json_each(?2))
\ No newline at end of file
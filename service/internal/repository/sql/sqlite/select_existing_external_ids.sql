@@ -0,0 +1,3 @@
+SELECT external_id
+FROM news
+WHERE external_id IN (SELECT value FROM json_each(?1));
\ No newline at end of file
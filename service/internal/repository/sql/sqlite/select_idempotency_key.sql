@@ -0,0 +1,4 @@
+SELECT request_hash, status_code, content_type, response_body
+FROM idempotency_keys
+WHERE client = ?1
+  AND key = ?2
\ No newline at end of file
@@ -0,0 +1,3 @@
+SELECT id, news_id, mime_type, size, checksum, alt_text, created_at, storage_key
+FROM media
+WHERE id = ?1
\ No newline at end of file
@@ -0,0 +1,31 @@
+SELECT id,
+       title,
+       content,
+       version,
+       updated_at,
+       external_id,
+       content_format,
+       content_html,
+       slug,
+       (SELECT json_group_array(nc.category_id ORDER BY nc.category_id)
+        FROM news_categories nc
+        WHERE nc.news_id = news.id) AS categories,
+       (SELECT json_group_array(t.name ORDER BY t.name)
+        FROM news_tags nt
+                 JOIN tags t ON t.id = nt.tag_id
+        WHERE nt.news_id = news.id) AS tags,
+       (SELECT json_group_array(json_object('ID', m.id, 'NewsID', m.news_id, 'MimeType', m.mime_type,
+                                            'Size', m.size, 'Checksum', m.checksum, 'AltText', m.alt_text,
+                                            'CreatedAt', replace(m.created_at, ' ', 'T'), 'StorageKey', m.storage_key)
+                                ORDER BY m.id)
+        FROM media m
+        WHERE m.news_id = news.id) AS media
+FROM news
+WHERE id IN (SELECT nt.news_id
+             FROM news_tags nt
+                      JOIN tags t ON t.id = nt.tag_id
+             WHERE t.name IN (SELECT value FROM json_each(?3))
+             GROUP BY nt.news_id
+             HAVING count(*) = ?4)
+ORDER BY id DESC
+    LIMIT ?1 OFFSET ?2;
\ No newline at end of file
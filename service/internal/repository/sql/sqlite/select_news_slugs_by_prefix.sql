@@ -0,0 +1,3 @@
+SELECT slug, news_id
+FROM news_slugs
+WHERE slug = ?1 OR slug LIKE ?1 || '-%';
\ No newline at end of file
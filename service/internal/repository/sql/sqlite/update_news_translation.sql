@@ -0,0 +1,8 @@
+UPDATE news_translations
+SET title          = ?3,
+    content        = ?4,
+    content_format = ?5,
+    content_html   = ?6,
+    updated_at     = ?7
+WHERE news_id = ?1
+  AND locale = ?2
\ No newline at end of file
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"service/internal/apperrors"
+	"service/internal/metrics"
+	"service/internal/models"
+	"service/pkg/blob"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// sniffLen - сколько первых байт файла нужно http.DetectContentType
+const sniffLen = 512
+
+// AttachMedia сохраняет файл в хранилище и прикрепляет его к новости. Тип файла определяется
+// по первым байтам содержимого: заявленному клиентом типу не доверяем.
+func (s *NewsService) AttachMedia(ctx context.Context, newsId int64, upload models.MediaUpload) (models.Media, error) {
+	body := bufio.NewReaderSize(upload.Body, sniffLen)
+	head, err := body.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return models.Media{}, fmt.Errorf("failed to read media: %w", err)
+	}
+	if len(head) == 0 {
+		return models.Media{}, apperrors.NewValidation("file cannot be empty")
+	}
+
+	mimeType := http.DetectContentType(head)
+	if !models.IsMediaType(mimeType) {
+		s.log.WithContext(ctx).WithFields(logrus.Fields{"news_id": newsId, "mime_type": mimeType}).Warn("Unsupported media type")
+		return models.Media{}, apperrors.NewUnsupportedMediaType(models.ErrMediaType.Error())
+	}
+
+	// Первые два символа ключа - подкаталог, чтобы в одном каталоге не копились все файлы
+	id := uuid.NewString()
+	key := id[:2] + "/" + id
+
+	hash := sha256.New()
+	size, err := s.media.Put(ctx, key, io.TeeReader(body, hash))
+	if err != nil {
+		s.log.WithContext(ctx).WithError(err).WithField("news_id", newsId).Error("Failed to store media")
+		return models.Media{}, fmt.Errorf("failed to store media: %w", err)
+	}
+
+	media := models.Media{
+		NewsID:     newsId,
+		MimeType:   mimeType,
+		Size:       size,
+		Checksum:   hex.EncodeToString(hash.Sum(nil)),
+		AltText:    upload.AltText,
+		StorageKey: key,
+	}
+	if err = s.repo.CreateMedia(ctx, &media); err != nil {
+		s.deleteBlob(ctx, key)
+		return models.Media{}, err
+	}
+
+	metrics.NewsEdited.Inc()
+	return media, nil
+}
+
+// OpenMedia возвращает описание вложения и его файл, файл закрывает вызывающий
+func (s *NewsService) OpenMedia(ctx context.Context, mediaId int64) (models.Media, io.ReadSeekCloser, error) {
+	media, err := s.repo.GetMedia(ctx, mediaId)
+	if err != nil {
+		return models.Media{}, nil, err
+	}
+
+	file, err := s.media.Open(ctx, media.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		// Запись есть, а файла нет - хранилище потеряло файл, клиенту это выглядит как отсутствие вложения
+		s.log.WithContext(ctx).WithField("media_id", mediaId).Error("Media file is missing in blob store")
+		return models.Media{}, nil, apperrors.NewNotFound("Media not found")
+	}
+	if err != nil {
+		s.log.WithContext(ctx).WithError(err).WithField("media_id", mediaId).Error("Failed to open media")
+		return models.Media{}, nil, fmt.Errorf("failed to open media: %w", err)
+	}
+
+	return media, file, nil
+}
+
+// deleteBlob удаляет файл, которому больше не соответствует запись. Ошибка только логируется:
+// запрос клиента уже выполнен, а лишний файл ничего не ломает.
+func (s *NewsService) deleteBlob(ctx context.Context, key string) {
+	if err := s.media.Delete(ctx, key); err != nil {
+		s.log.WithContext(ctx).WithError(err).WithField("storage_key", key).Error("Failed to delete media file")
+	}
+}
@@ -1,62 +1,227 @@
 package service
 
 import (
+	"context"
+	"io"
+	"service/internal/metrics"
 	"service/internal/models"
 	"service/internal/repository"
+	"service/pkg/blob"
 
 	"github.com/sirupsen/logrus"
 )
 
 //go:generate mockery --name=INewsService --output=mocks --outpkg=mocks --case=snake --with-expecter
 type INewsService interface {
-	CreateNews(createForm models.NewsCreateForm) (int64, error)
-	EditNews(newsId int64, editForm models.NewsEditForm) error
-	ListNews(limit, offset int64) ([]models.NewsWithCategories, error)
+	CreateNews(ctx context.Context, createForm models.NewsCreateForm) (int64, error)
+	EditNews(ctx context.Context, newsId int64, editForm models.NewsEditForm) error
+	ListNews(ctx context.Context, limit, offset int64, filter models.NewsFilter) ([]models.NewsWithCategories, error)
+	GetNews(ctx context.Context, newsId int64) (models.NewsWithCategories, error)
+	GetNewsBySlug(ctx context.Context, slug string) (models.NewsWithCategories, error)
+	BatchNews(ctx context.Context, items []models.NewsBatchItem, atomic bool) ([]models.NewsBatchResult, error)
+	DeleteNews(ctx context.Context, newsId int64) error
+	ExportNews(ctx context.Context, fn func(models.NewsWithCategories) error) error
+	ImportNews(ctx context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error)
+	CreateTranslation(ctx context.Context, newsId int64, locale string, createForm models.NewsTranslationCreateForm) error
+	EditTranslation(ctx context.Context, newsId int64, locale string, editForm models.NewsTranslationEditForm) error
+	LocalizeNews(ctx context.Context, newsList []models.NewsWithCategories, locales []string) error
+	ListTags(ctx context.Context, prefix string, limit int64) ([]models.TagCount, error)
+	AttachMedia(ctx context.Context, newsId int64, upload models.MediaUpload) (models.Media, error)
+	OpenMedia(ctx context.Context, mediaId int64) (models.Media, io.ReadSeekCloser, error)
 }
 type NewsService struct {
-	repo repository.INewsRepository
-	log  *logrus.Logger
+	repo  repository.INewsRepository
+	media blob.BlobStore
+	log   *logrus.Logger
 }
 
-func NewNewsService(repo repository.INewsRepository, log *logrus.Logger) INewsService {
+// NewNewsService создаёт сервис новостей, файлы вложений хранятся в media
+func NewNewsService(repo repository.INewsRepository, media blob.BlobStore, log *logrus.Logger) INewsService {
 	return &NewsService{
-		repo: repo,
-		log:  log,
+		repo:  repo,
+		media: media,
+		log:   log,
 	}
 }
 
-func (s *NewsService) CreateNews(editForm models.NewsCreateForm) (int64, error) {
-	return s.repo.CreateNews(editForm)
+func (s *NewsService) CreateNews(ctx context.Context, editForm models.NewsCreateForm) (int64, error) {
+	id, err := s.repo.CreateNews(ctx, editForm)
+	if err != nil {
+		return 0, err
+	}
+
+	metrics.NewsCreated.Inc()
+	return id, nil
 }
 
-func (s *NewsService) EditNews(newsId int64, editForm models.NewsEditForm) error {
-	updateFields := make(map[string]interface{})
-	if editForm.Title != nil {
-		updateFields["title"] = editForm.Title
-	}
-	if editForm.Content != nil {
-		updateFields["content"] = editForm.Content
-	}
+func (s *NewsService) EditNews(ctx context.Context, newsId int64, editForm models.NewsEditForm) error {
+	updateFields := editForm.UpdateFields()
+	categories := editForm.CategoryChanges()
 
 	// Обновляем поля новости
-	if len(updateFields) > 0 || editForm.Categories != nil {
-		if err := s.repo.UpdateNews(newsId, updateFields, editForm.Categories); err != nil {
-			logrus.Error(err)
+	if len(updateFields) > 0 || !categories.IsEmpty() {
+		if err := s.repo.UpdateNews(ctx, newsId, updateFields, categories); err != nil {
+			s.log.WithContext(ctx).Error(err)
 			return err
 		}
+		metrics.NewsEdited.Inc()
 	}
 
 	return nil
 }
 
-func (s *NewsService) ListNews(limit, offset int64) ([]models.NewsWithCategories, error) {
+func (s *NewsService) ListNews(ctx context.Context, limit, offset int64, filter models.NewsFilter) ([]models.NewsWithCategories, error) {
 	//добавить валидацию лимита и оффсета
 	var newsList []models.NewsWithCategories
 
-	newsList, err := s.repo.GetNews(limit, offset)
+	newsList, err := s.repo.GetNews(ctx, limit, offset, filter)
 	if err != nil {
 		return newsList, err
 	}
 
 	return newsList, nil
 }
+
+func (s *NewsService) GetNews(ctx context.Context, newsId int64) (models.NewsWithCategories, error) {
+	return s.repo.GetNewsByID(ctx, newsId)
+}
+
+// GetNewsBySlug находит новость по текущему или прежнему slug, текущий - в Slug новости
+func (s *NewsService) GetNewsBySlug(ctx context.Context, slug string) (models.NewsWithCategories, error) {
+	return s.repo.GetNewsBySlug(ctx, slug)
+}
+
+func (s *NewsService) BatchNews(ctx context.Context, items []models.NewsBatchItem, atomic bool) ([]models.NewsBatchResult, error) {
+	if len(items) == 0 {
+		return []models.NewsBatchResult{}, nil
+	}
+
+	results, err := s.repo.ApplyBatch(ctx, items, atomic)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+		switch result.Op {
+		case models.BatchOpCreate:
+			metrics.NewsCreated.Inc()
+		case models.BatchOpEdit:
+			metrics.NewsEdited.Inc()
+		}
+	}
+
+	return results, nil
+}
+
+// DeleteNews удаляет новость, а после неё файлы её вложений. Файлы удаляются уже после коммита:
+// сбой оставит в хранилище лишний файл, но не запись о вложении без файла.
+func (s *NewsService) DeleteNews(ctx context.Context, newsId int64) error {
+	media, err := s.repo.DeleteNews(ctx, newsId)
+	if err != nil {
+		return err
+	}
+	for _, m := range media {
+		s.deleteBlob(ctx, m.StorageKey)
+	}
+
+	metrics.NewsDeleted.Inc()
+	return nil
+}
+
+func (s *NewsService) ExportNews(ctx context.Context, fn func(models.NewsWithCategories) error) error {
+	return s.repo.ExportNews(ctx, fn)
+}
+
+func (s *NewsService) ImportNews(ctx context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error) {
+	if len(items) == 0 {
+		return []models.NewsImportResult{}, nil
+	}
+
+	results, err := s.repo.ImportNews(ctx, items, dryRun)
+	if err != nil || dryRun {
+		return results, err
+	}
+
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+		switch result.Action {
+		case models.ImportActionCreate:
+			metrics.NewsCreated.Inc()
+		case models.ImportActionUpdate:
+			metrics.NewsEdited.Inc()
+		}
+	}
+
+	return results, nil
+}
+
+func (s *NewsService) CreateTranslation(ctx context.Context, newsId int64, locale string, createForm models.NewsTranslationCreateForm) error {
+	if err := s.repo.CreateTranslation(ctx, newsId, locale, createForm); err != nil {
+		return err
+	}
+
+	metrics.NewsEdited.Inc()
+	return nil
+}
+
+func (s *NewsService) EditTranslation(ctx context.Context, newsId int64, locale string, editForm models.NewsTranslationEditForm) error {
+	updateFields := editForm.UpdateFields()
+	if len(updateFields) == 0 {
+		return nil
+	}
+
+	if err := s.repo.UpdateTranslation(ctx, newsId, locale, updateFields); err != nil {
+		s.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	metrics.NewsEdited.Inc()
+	return nil
+}
+
+// LocalizeNews подменяет текст каждой новости первым найденным переводом из цепочки locales.
+// Новости без подходящего перевода остаются на языке по умолчанию.
+func (s *NewsService) LocalizeNews(ctx context.Context, newsList []models.NewsWithCategories, locales []string) error {
+	if len(newsList) == 0 || len(locales) == 0 {
+		return nil
+	}
+
+	newsIds := make([]int64, 0, len(newsList))
+	for _, n := range newsList {
+		newsIds = append(newsIds, n.ID)
+	}
+
+	translations, err := s.repo.GetTranslations(ctx, newsIds, locales)
+	if err != nil {
+		return err
+	}
+
+	byNews := make(map[int64]map[string]models.NewsTranslation)
+	for _, t := range translations {
+		if byNews[t.NewsID] == nil {
+			byNews[t.NewsID] = make(map[string]models.NewsTranslation)
+		}
+		byNews[t.NewsID][t.Locale] = t
+	}
+
+	for i := range newsList {
+		for _, locale := range locales {
+			if t, ok := byNews[newsList[i].ID][locale]; ok {
+				newsList[i].Translate(t)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListTags подсказывает теги по началу prefix, prefix уже нормализован
+func (s *NewsService) ListTags(ctx context.Context, prefix string, limit int64) ([]models.TagCount, error) {
+	return s.repo.GetTags(ctx, prefix, limit)
+}
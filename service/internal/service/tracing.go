@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"io"
+	"service/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("service/internal/service")
+
+// tracedNewsService оборачивает каждый метод INewsService в span
+type tracedNewsService struct {
+	next INewsService
+}
+
+func NewTracedNewsService(next INewsService) INewsService {
+	return &tracedNewsService{next: next}
+}
+
+func (t *tracedNewsService) CreateNews(ctx context.Context, createForm models.NewsCreateForm) (int64, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.CreateNews")
+	id, err := t.next.CreateNews(ctx, createForm)
+	span.SetAttributes(attribute.Int64("news.id", id))
+	endSpan(span, err)
+	return id, err
+}
+
+func (t *tracedNewsService) EditNews(ctx context.Context, newsId int64, editForm models.NewsEditForm) error {
+	ctx, span := tracer.Start(ctx, "NewsService.EditNews", trace.WithAttributes(attribute.Int64("news.id", newsId)))
+	err := t.next.EditNews(ctx, newsId, editForm)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedNewsService) ListNews(ctx context.Context, limit, offset int64, filter models.NewsFilter) ([]models.NewsWithCategories, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.ListNews", trace.WithAttributes(
+		attribute.Int64("limit", limit),
+		attribute.Int64("offset", offset),
+		attribute.StringSlice("news.tags", filter.Tags),
+	))
+	newsList, err := t.next.ListNews(ctx, limit, offset, filter)
+	endSpan(span, err)
+	return newsList, err
+}
+
+func (t *tracedNewsService) GetNews(ctx context.Context, newsId int64) (models.NewsWithCategories, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.GetNews", trace.WithAttributes(attribute.Int64("news.id", newsId)))
+	news, err := t.next.GetNews(ctx, newsId)
+	endSpan(span, err)
+	return news, err
+}
+
+func (t *tracedNewsService) GetNewsBySlug(ctx context.Context, slug string) (models.NewsWithCategories, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.GetNewsBySlug", trace.WithAttributes(attribute.String("news.slug", slug)))
+	news, err := t.next.GetNewsBySlug(ctx, slug)
+	endSpan(span, err)
+	return news, err
+}
+
+func (t *tracedNewsService) CreateTranslation(ctx context.Context, newsId int64, locale string, createForm models.NewsTranslationCreateForm) error {
+	ctx, span := tracer.Start(ctx, "NewsService.CreateTranslation", trace.WithAttributes(
+		attribute.Int64("news.id", newsId),
+		attribute.String("news.locale", locale),
+	))
+	err := t.next.CreateTranslation(ctx, newsId, locale, createForm)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedNewsService) EditTranslation(ctx context.Context, newsId int64, locale string, editForm models.NewsTranslationEditForm) error {
+	ctx, span := tracer.Start(ctx, "NewsService.EditTranslation", trace.WithAttributes(
+		attribute.Int64("news.id", newsId),
+		attribute.String("news.locale", locale),
+	))
+	err := t.next.EditTranslation(ctx, newsId, locale, editForm)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedNewsService) LocalizeNews(ctx context.Context, newsList []models.NewsWithCategories, locales []string) error {
+	ctx, span := tracer.Start(ctx, "NewsService.LocalizeNews", trace.WithAttributes(
+		attribute.Int("news.count", len(newsList)),
+		attribute.StringSlice("locales", locales),
+	))
+	err := t.next.LocalizeNews(ctx, newsList, locales)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedNewsService) ListTags(ctx context.Context, prefix string, limit int64) ([]models.TagCount, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.ListTags", trace.WithAttributes(
+		attribute.String("tags.prefix", prefix),
+		attribute.Int64("limit", limit),
+	))
+	tags, err := t.next.ListTags(ctx, prefix, limit)
+	endSpan(span, err)
+	return tags, err
+}
+
+func (t *tracedNewsService) AttachMedia(ctx context.Context, newsId int64, upload models.MediaUpload) (models.Media, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.AttachMedia", trace.WithAttributes(attribute.Int64("news.id", newsId)))
+	media, err := t.next.AttachMedia(ctx, newsId, upload)
+	span.SetAttributes(
+		attribute.Int64("media.id", media.ID),
+		attribute.String("media.mime_type", media.MimeType),
+		attribute.Int64("media.size", media.Size),
+	)
+	endSpan(span, err)
+	return media, err
+}
+
+func (t *tracedNewsService) OpenMedia(ctx context.Context, mediaId int64) (models.Media, io.ReadSeekCloser, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.OpenMedia", trace.WithAttributes(attribute.Int64("media.id", mediaId)))
+	media, file, err := t.next.OpenMedia(ctx, mediaId)
+	endSpan(span, err)
+	return media, file, err
+}
+
+func (t *tracedNewsService) BatchNews(ctx context.Context, items []models.NewsBatchItem, atomic bool) ([]models.NewsBatchResult, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.BatchNews", trace.WithAttributes(
+		attribute.Int("batch.size", len(items)),
+		attribute.Bool("batch.atomic", atomic),
+	))
+	results, err := t.next.BatchNews(ctx, items, atomic)
+	endSpan(span, err)
+	return results, err
+}
+
+func (t *tracedNewsService) DeleteNews(ctx context.Context, newsId int64) error {
+	ctx, span := tracer.Start(ctx, "NewsService.DeleteNews", trace.WithAttributes(attribute.Int64("news.id", newsId)))
+	err := t.next.DeleteNews(ctx, newsId)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedNewsService) ExportNews(ctx context.Context, fn func(models.NewsWithCategories) error) error {
+	ctx, span := tracer.Start(ctx, "NewsService.ExportNews")
+	exported := 0
+	err := t.next.ExportNews(ctx, func(news models.NewsWithCategories) error {
+		exported++
+		return fn(news)
+	})
+	span.SetAttributes(attribute.Int("export.count", exported))
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedNewsService) ImportNews(ctx context.Context, items []models.NewsImportItem, dryRun bool) ([]models.NewsImportResult, error) {
+	ctx, span := tracer.Start(ctx, "NewsService.ImportNews", trace.WithAttributes(
+		attribute.Int("import.size", len(items)),
+		attribute.Bool("import.dry_run", dryRun),
+	))
+	results, err := t.next.ImportNews(ctx, items, dryRun)
+	endSpan(span, err)
+	return results, err
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"service/internal/configs"
+	"service/internal/health"
+	"service/internal/metrics"
+	"service/internal/repository"
+	"service/pkg/db"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// storage - репозитории выбранного хранилища, их проверки готовности и закрытие
+type storage struct {
+	news        repository.INewsRepository
+	idempotency repository.IIdempotencyRepository
+	close       func() error
+}
+
+// newStorage открывает хранилище из cnf.Storage и добавляет его проверки в checker.
+// Фоновые задачи хранилища работают до закрытия done.
+func newStorage(ctx context.Context, cnf configs.Config, checker *health.Checker, done <-chan struct{}, log *logrus.Logger) (storage, error) {
+	switch cnf.Storage {
+	case configs.StorageMemory:
+		log.Warn("Using in-memory storage, data will be lost on restart")
+		return storage{
+			news:        repository.NewMemoryNewsRepository(log),
+			idempotency: repository.NewMemoryIdempotencyRepository(log),
+			close:       func() error { return nil },
+		}, nil
+	case configs.StorageDatabase:
+		return newDatabaseStorage(ctx, cnf.Database, checker, done, log)
+	default:
+		return storage{}, fmt.Errorf("unknown storage %q", cnf.Storage)
+	}
+}
+
+// newDatabaseStorage открывает Postgres или файл SQLite. Реплики бывают только у Postgres,
+// ключи идемпотентности в SQLite блокируются внутри процесса.
+func newDatabaseStorage(ctx context.Context, cnf configs.Database, checker *health.Checker, done <-chan struct{}, log *logrus.Logger) (storage, error) {
+	database, reform, err := db.InitReformDB(ctx, cnf, log)
+	if err != nil {
+		return storage{}, fmt.Errorf("failed to init reform db: %w", err)
+	}
+
+	if err = metrics.RegisterDB(database, cnf.Name); err != nil {
+		return storage{}, fmt.Errorf("failed to register db metrics: %w", err)
+	}
+
+	migrator, err := db.NewMigrator(database, cnf.Driver)
+	if err != nil {
+		return storage{}, err
+	}
+
+	replicas, err := db.InitReplicas(ctx, cnf, log)
+	if err != nil {
+		return storage{}, fmt.Errorf("failed to init replicas: %w", err)
+	}
+
+	for name, pool := range replicas.Pools() {
+		if err = metrics.RegisterDB(pool, cnf.Name+"-"+name); err != nil {
+			return storage{}, fmt.Errorf("failed to register replica metrics: %w", err)
+		}
+	}
+
+	checker.Add("database", database.PingContext)
+	checker.Add("migrations", func(ctx context.Context) error {
+		return db.CheckMigrations(ctx, migrator)
+	})
+
+	go replicas.Watch(
+		done,
+		time.Duration(cnf.ReplicaCheckInterval)*time.Second,
+		time.Duration(cnf.ReplicaCheckTimeout)*time.Second,
+	)
+
+	return storage{
+		news:        repository.NewNewsRepository(reform, replicas, log),
+		idempotency: repository.NewIdempotencyRepository(reform, log),
+		close: func() error {
+			if err := replicas.Close(); err != nil {
+				database.Close()
+				return fmt.Errorf("error close replicas: %w", err)
+			}
+			if err := database.Close(); err != nil {
+				return fmt.Errorf("error close database: %w", err)
+			}
+			return nil
+		},
+	}, nil
+}
@@ -0,0 +1,8 @@
+// Package migrations встраивает SQL миграции в бинарник, чтобы сервису и cmd/migrate не нужна была папка на диске.
+// Миграции каждого драйвера лежат в своей папке, номера версий у драйверов совпадают.
+package migrations
+
+import "embed"
+
+//go:embed postgres/*.sql sqlite/*.sql
+var FS embed.FS
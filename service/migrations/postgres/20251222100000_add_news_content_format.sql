@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE news
+    ADD COLUMN IF NOT EXISTS content_format VARCHAR(16) NOT NULL DEFAULT 'plain'
+        CHECK (content_format IN ('plain', 'markdown', 'html')),
+    ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+
+-- Существующие новости - простой текст, HTML собирается так же, как content.Render для plain
+UPDATE news
+SET content_html = '<p>' || REPLACE(REPLACE(REPLACE(REPLACE(content,
+    '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), CHR(10), '<br>' || CHR(10)) || '</p>';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE news
+    DROP COLUMN IF EXISTS content_html,
+    DROP COLUMN IF EXISTS content_format;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE news
+    ADD COLUMN IF NOT EXISTS slug VARCHAR(255) NOT NULL DEFAULT '';
+
+-- news_slugs хранит текущий и все прежние slug новости, старые адреса перенаправляются на текущий
+CREATE TABLE IF NOT EXISTS news_slugs (
+    slug VARCHAR(255) PRIMARY KEY,
+    news_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_news FOREIGN KEY (news_id) REFERENCES news(id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_news_slugs_news_id ON news_slugs (news_id);
+
+-- Транслитерации в SQL нет: существующие новости получают slug по id,
+-- читаемый slug появится при следующем изменении заголовка
+UPDATE news SET slug = 'news-' || id;
+INSERT INTO news_slugs (slug, news_id) SELECT slug, id FROM news;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS news_slugs;
+
+ALTER TABLE news
+    DROP COLUMN IF EXISTS slug;
+-- +goose StatementEnd
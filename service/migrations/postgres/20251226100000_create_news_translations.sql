@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS news_translations (
+    news_id BIGINT NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    content_format VARCHAR(16) NOT NULL DEFAULT 'plain'
+        CHECK (content_format IN ('plain', 'markdown', 'html')),
+    content_html TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (news_id, locale),
+    CONSTRAINT fk_news FOREIGN KEY (news_id) REFERENCES news(id)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS news_translations;
+-- +goose StatementEnd
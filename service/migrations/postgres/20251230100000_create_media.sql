@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Файлы новостей: здесь только описание, содержимое лежит в хранилище файлов под storage_key
+CREATE TABLE IF NOT EXISTS media (
+    id BIGSERIAL PRIMARY KEY,
+    news_id BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    mime_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    alt_text VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_news FOREIGN KEY (news_id) REFERENCES news(id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_media_news_id ON media (news_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS media;
+-- +goose StatementEnd
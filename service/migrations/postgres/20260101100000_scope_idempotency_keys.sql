@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ключ принадлежит клиенту, а выполняющийся запрос занимает его арендой вместо транзакции.
+-- Ключи без ответа остались от запросов, державших транзакцию, и больше никем не заняты.
+DELETE FROM idempotency_keys WHERE status_code IS NULL;
+
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS client VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS lock_token CHAR(36);
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (client, key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Без клиента одинаковые ключи разных клиентов не поместятся в прежний первичный ключ
+DELETE FROM idempotency_keys WHERE client <> '' OR status_code IS NULL;
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
+
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS lock_token;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS client;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Время прежних slug хранится с часовым поясом, как news.updated_at. Приложение писало его в UTC.
+ALTER TABLE news_slugs
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at SET DEFAULT now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE news_slugs
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Без часового пояса updated_at перевода читался в зоне сессии базы и сдвигался при отдаче.
+-- Приложение писало его в UTC.
+ALTER TABLE news_translations
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at SET DEFAULT now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE news_translations
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+-- +goose StatementEnd
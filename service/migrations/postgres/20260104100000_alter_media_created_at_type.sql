@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- created_at вложения отдаётся в Last-Modified и в JSON новости и должен читаться одинаково
+-- при любой зоне сессии. Приложение писало его в UTC.
+ALTER TABLE media
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at SET DEFAULT now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE media
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE news ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+-- SQLite не добавляет колонку с вычисляемым значением по умолчанию, заполняем отдельно
+ALTER TABLE news ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE news SET updated_at = CURRENT_TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE news DROP COLUMN updated_at;
+ALTER TABLE news DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE news ADD COLUMN external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS news_external_id_key ON news (external_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS news_external_id_key;
+
+ALTER TABLE news DROP COLUMN external_id;
+-- +goose StatementEnd
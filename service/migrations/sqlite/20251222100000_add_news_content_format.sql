@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE news ADD COLUMN content_format VARCHAR(16) NOT NULL DEFAULT 'plain'
+    CHECK (content_format IN ('plain', 'markdown', 'html'));
+ALTER TABLE news ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
+
+-- Существующие новости - простой текст, HTML собирается так же, как content.Render для plain
+UPDATE news
+SET content_html = '<p>' || REPLACE(REPLACE(REPLACE(REPLACE(content,
+    '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), CHAR(10), '<br>' || CHAR(10)) || '</p>';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE news DROP COLUMN content_html;
+ALTER TABLE news DROP COLUMN content_format;
+-- +goose StatementEnd
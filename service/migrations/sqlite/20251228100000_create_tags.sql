@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Теги - свободная таксономия рядом с категориями, имя хранится нормализованным (см. models.NormalizeTag)
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(64) NOT NULL UNIQUE
+    );
+
+CREATE TABLE IF NOT EXISTS news_tags (
+    news_id BIGINT NOT NULL,
+    tag_id BIGINT NOT NULL,
+    PRIMARY KEY (news_id, tag_id),
+    CONSTRAINT fk_news FOREIGN KEY (news_id) REFERENCES news(id),
+    CONSTRAINT fk_tag FOREIGN KEY (tag_id) REFERENCES tags(id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_news_tags_tag_id ON news_tags (tag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS news_tags;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ключ принадлежит клиенту, а выполняющийся запрос занимает его арендой.
+-- SQLite не меняет первичный ключ, поэтому таблица пересоздаётся.
+CREATE TABLE idempotency_keys_new (
+    client VARCHAR(64) NOT NULL DEFAULT '',
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INT,
+    content_type VARCHAR(255),
+    response_body BLOB,
+    lock_token CHAR(36),
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (client, key)
+    );
+
+INSERT INTO idempotency_keys_new (key, request_hash, status_code, content_type, response_body, created_at, expires_at)
+SELECT key, request_hash, status_code, content_type, response_body, created_at, expires_at
+FROM idempotency_keys
+WHERE status_code IS NOT NULL;
+
+DROP TABLE idempotency_keys;
+ALTER TABLE idempotency_keys_new RENAME TO idempotency_keys;
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE idempotency_keys_old (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64) NOT NULL,
+    status_code INT,
+    content_type VARCHAR(255),
+    response_body BLOB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+    );
+
+-- Без клиента одинаковые ключи разных клиентов не поместятся в прежний первичный ключ
+INSERT INTO idempotency_keys_old (key, request_hash, status_code, content_type, response_body, created_at, expires_at)
+SELECT key, request_hash, status_code, content_type, response_body, created_at, expires_at
+FROM idempotency_keys
+WHERE client = '' AND status_code IS NOT NULL;
+
+DROP TABLE idempotency_keys;
+ALTER TABLE idempotency_keys_old RENAME TO idempotency_keys;
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrNotFound - файла с таким ключом нет
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey - ключ пустой или выходит за пределы хранилища
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore хранит файлы по ключу. Ключ - относительный путь через "/", его выбирает вызывающий код.
+// Реализация на локальном диске подходит для одного экземпляра сервиса;
+// чтобы файлы видели все реплики, нужна реализация поверх общего хранилища.
+type BlobStore interface {
+	// Put записывает r под ключом key и возвращает число записанных байт.
+	// При ошибке частично записанный файл под ключом не остаётся.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open открывает файл на чтение. Seek нужен, чтобы отдавать части файла по Range.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete удаляет файл, отсутствие файла ошибкой не считается
+	Delete(ctx context.Context, key string) error
+}
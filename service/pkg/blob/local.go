@@ -0,0 +1,124 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore хранит файлы в каталоге на локальном диске, ключ - путь внутри каталога
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore создаёт каталог dir, если его ещё нет
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob dir: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// OpenLocalStore открывает уже существующий каталог dir и не создаёт его.
+// Утилиты на чужой машине так не примут пустой новый каталог за хранилище сервиса.
+func OpenLocalStore(dir string) (*LocalStore, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob dir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("failed to open blob dir: %s is not a directory", dir)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put пишет во временный файл рядом с целевым и переименовывает его,
+// так читатели не видят недописанный файл
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create blob dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, contextReader{ctx: ctx, r: r})
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to save blob: %w", err)
+	}
+
+	return written, nil
+}
+
+func (s *LocalStore) Open(_ context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+
+	return file, nil
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// path переводит ключ в путь внутри каталога, ключи с ".." и абсолютные пути отклоняются
+func (s *LocalStore) path(key string) (string, error) {
+	local := filepath.FromSlash(key)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(s.dir, local), nil
+}
+
+// contextReader прерывает копирование, когда клиент отменил запрос
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,104 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "media"))
+	require.NoError(t, err)
+
+	t.Run("put and open", func(t *testing.T) {
+		written, err := store.Put(ctx, "ab/abc", strings.NewReader("hello"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), written)
+
+		file, err := store.Open(ctx, "ab/abc")
+		require.NoError(t, err)
+		defer file.Close()
+
+		_, err = file.Seek(1, io.SeekStart)
+		require.NoError(t, err)
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, "ello", string(data))
+	})
+
+	t.Run("failed put leaves nothing", func(t *testing.T) {
+		_, err := store.Put(ctx, "ab/broken", io.MultiReader(strings.NewReader("partial"), errReader{}))
+		require.Error(t, err)
+
+		_, err = store.Open(ctx, "ab/broken")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		entries, err := os.ReadDir(filepath.Join(dir, "media", "ab"))
+		require.NoError(t, err)
+		for _, entry := range entries {
+			assert.False(t, strings.HasPrefix(entry.Name(), ".upload-"), "temporary file %s left", entry.Name())
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := store.Put(canceled, "ab/canceled", strings.NewReader("data"))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := store.Put(ctx, "cd/file", strings.NewReader("data"))
+		require.NoError(t, err)
+
+		require.NoError(t, store.Delete(ctx, "cd/file"))
+		_, err = store.Open(ctx, "cd/file")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		assert.NoError(t, store.Delete(ctx, "cd/file"), "deleting a missing blob is not an error")
+	})
+
+	t.Run("keys outside the dir", func(t *testing.T) {
+		for _, key := range []string{"", "../escape", "ab/../../escape", "/etc/passwd"} {
+			_, err := store.Put(ctx, key, strings.NewReader("data"))
+			assert.ErrorIs(t, err, ErrInvalidKey, key)
+			_, err = store.Open(ctx, key)
+			assert.ErrorIs(t, err, ErrInvalidKey, key)
+		}
+	})
+}
+
+func TestOpenLocalStore(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := OpenLocalStore(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoDirExists(t, filepath.Join(dir, "missing"), "the dir is not created")
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o644))
+	_, err = OpenLocalStore(file)
+	assert.Error(t, err)
+
+	store, err := OpenLocalStore(dir)
+	require.NoError(t, err)
+	_, err = store.Put(context.Background(), "ab/abc", strings.NewReader("hello"))
+	assert.NoError(t, err)
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"net/url"
+	"service/internal/configs"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DSN возвращает строку подключения: для SQLite путь к файлу с настройками по умолчанию,
+// для Postgres DB_DSN как есть, иначе URL из отдельных параметров
+func DSN(cnf configs.Database) string {
+	if cnf.Driver == configs.DriverSQLite {
+		return sqliteDSN(cnf.DSN)
+	}
+	if cnf.DSN != "" {
+		return cnf.DSN
+	}
+
+	query := url.Values{}
+	query.Set("sslmode", cnf.SSLMode)
+	if cnf.SSLRootCert != "" {
+		query.Set("sslrootcert", cnf.SSLRootCert)
+	}
+	if cnf.SSLCert != "" {
+		query.Set("sslcert", cnf.SSLCert)
+		query.Set("sslkey", cnf.SSLKey)
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(cnf.User, cnf.Password),
+		Host:     net.JoinHostPort(cnf.Host, cnf.Port),
+		Path:     "/" + cnf.Name,
+		RawQuery: query.Encode(),
+	}
+
+	return dsn.String()
+}
+
+// pingWithRetry ждёт доступности базы с экспоненциальной задержкой между попытками.
+// Postgres в compose часто поднимается позже сервиса, поэтому первая неудача не фатальна.
+func pingWithRetry(ctx context.Context, db *sql.DB, cnf configs.Database, log *logrus.Logger) error {
+	delay := time.Duration(cnf.ConnectRetryDelay) * time.Millisecond
+	maxDelay := time.Duration(cnf.ConnectRetryMax) * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= cnf.ConnectRetries {
+			return err
+		}
+
+		log.WithError(err).WithFields(logrus.Fields{
+			"attempt": attempt + 1,
+			"retries": cnf.ConnectRetries,
+			"delay":   delay.String(),
+		}).Warn("database is not available, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, maxDelay)
+	}
+}
@@ -1,55 +1,92 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
-	"os"
 	"service/internal/configs"
 	"time"
 
-	"github.com/pressly/goose/v3"
+	"github.com/XSAM/otelsql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"gopkg.in/reform.v1"
 	"gopkg.in/reform.v1/dialects/postgresql"
+	"gopkg.in/reform.v1/dialects/sqlite3"
 )
 
-func InitReformDB(cnf configs.Database) (*sql.DB, *reform.DB, error) {
-	dbURL := fmt.Sprintf(
-		"postgresql://%s:%s@%s:%s/%s?sslmode=disable",
-		cnf.User,
-		cnf.Password,
-		cnf.Host,
-		cnf.Port,
-		cnf.Name,
-	)
-
-	db, err := sql.Open("postgres", dbURL)
+func InitReformDB(ctx context.Context, cnf configs.Database, log *logrus.Logger) (*sql.DB, *reform.DB, error) {
+	db, err := Open(ctx, cnf, log)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, nil, err
 	}
 
-	if err = db.Ping(); err != nil {
-		return nil, nil, fmt.Errorf("failed to ping database: %w", err)
+	if cnf.AutoMigrate {
+		if err = autoMigrate(ctx, db, cnf.Driver, log); err != nil {
+			db.Close()
+			return nil, nil, err
+		}
 	}
 
-	db.SetMaxOpenConns(cnf.MaxOpenConnection)
-	db.SetConnMaxLifetime(time.Minute * time.Duration(cnf.MaxLifeTime))
+	logger := NewQueryLogger(
+		log,
+		cnf.LogMode,
+		time.Duration(cnf.SlowQueryThreshold)*time.Millisecond,
+		cnf.LogRedactArgs,
+	)
+	reformDB := reform.NewDB(db, Dialect(cnf.Driver), logger)
 
-	if err = initMigration(db); err != nil {
-		return nil, nil, err
+	return db, reformDB, nil
+}
+
+// Open открывает основную базу и ждёт её доступности, миграции не запускаются
+func Open(ctx context.Context, cnf configs.Database, log *logrus.Logger) (*sql.DB, error) {
+	db, err := open(DSN(cnf), cnf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	//про логгер подумать
-	logger := log.New(os.Stderr, "SQL: ", log.Flags())
-	reformDB := reform.NewDB(db, postgresql.Dialect, reform.NewPrintfLogger(logger.Printf))
+	if err = pingWithRetry(ctx, db, cnf, log); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
 
-	return db, reformDB, nil
+	return db, nil
+}
+
+// Dialect возвращает диалект reform для драйвера из конфига
+func Dialect(driver string) reform.Dialect {
+	if driver == configs.DriverSQLite {
+		return sqlite3.Dialect
+	}
+	return postgresql.Dialect
 }
 
-func initMigration(db *sql.DB) error {
-	err := goose.Up(db, "migrations")
+// open открывает пул с трассировкой запросов и настройками пула из конфига
+func open(dsn string, cnf configs.Database) (*sql.DB, error) {
+	driverName, system := "postgres", semconv.DBSystemNamePostgreSQL
+	if cnf.Driver == configs.DriverSQLite {
+		driverName, system = "sqlite3", semconv.DBSystemNameSQLite
+	}
+
+	db, err := otelsql.Open(driverName, dsn,
+		otelsql.WithAttributes(system),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitConnectorConnect: true,
+			OmitRows:             true,
+		}),
+	)
 	if err != nil {
-		return fmt.Errorf("failed up migrations: %w", err)
+		return nil, err
 	}
-	return nil
+
+	db.SetMaxOpenConns(cnf.MaxOpenConnection)
+	db.SetMaxIdleConns(cnf.MaxIdleConnection)
+	db.SetConnMaxLifetime(time.Minute * time.Duration(cnf.MaxLifeTime))
+	db.SetConnMaxIdleTime(time.Minute * time.Duration(cnf.MaxIdleTime))
+
+	return db, nil
 }
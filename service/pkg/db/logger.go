@@ -0,0 +1,87 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+)
+
+const (
+	LogModeOff  = "off"
+	LogModeSlow = "slow"
+	LogModeAll  = "all"
+)
+
+// QueryLogger - reform.Logger поверх logrus.
+// В режиме slow пишет только медленные и упавшие запросы, в режиме all - все.
+// reform не передаёт логгеру context запроса, поэтому в строках SQL нет request_id и trace_id.
+// Упавший запрос сопровождает строка ошибки репозитория с этими полями, у медленных запросов их нет.
+type QueryLogger struct {
+	log           *logrus.Logger
+	mode          string
+	slowThreshold time.Duration
+	redactArgs    bool
+}
+
+var _ reform.Logger = (*QueryLogger)(nil)
+
+func NewQueryLogger(log *logrus.Logger, mode string, slowThreshold time.Duration, redactArgs bool) *QueryLogger {
+	return &QueryLogger{
+		log:           log,
+		mode:          mode,
+		slowThreshold: slowThreshold,
+		redactArgs:    redactArgs,
+	}
+}
+
+// Before ничего не пишет: всё нужное, включая длительность, известно только в After
+func (l *QueryLogger) Before(string, []interface{}) {}
+
+func (l *QueryLogger) After(query string, args []interface{}, d time.Duration, err error) {
+	if l.mode == LogModeOff {
+		return
+	}
+
+	// Откат после коммита - штатный отложенный rollback, вызывающий код сам игнорирует ErrTxDone
+	if query == "ROLLBACK" && errors.Is(err, sql.ErrTxDone) {
+		return
+	}
+
+	slow := d >= l.slowThreshold
+	if l.mode == LogModeSlow && !slow && err == nil {
+		return
+	}
+
+	entry := l.log.WithFields(logrus.Fields{
+		"query":       query,
+		"args":        l.formatArgs(args),
+		"duration_ms": float64(d.Microseconds()) / 1000,
+		"slow":        slow,
+	})
+
+	switch {
+	case err != nil:
+		entry.WithError(err).Error("SQL query failed")
+	case slow:
+		entry.Warn("Slow SQL query")
+	default:
+		entry.Info("SQL query")
+	}
+}
+
+// formatArgs скрывает значения аргументов, оставляя только их типы: в запросах бывают персональные данные
+func (l *QueryLogger) formatArgs(args []interface{}) []string {
+	formatted := make([]string, len(args))
+	for i, arg := range args {
+		if l.redactArgs {
+			formatted[i] = fmt.Sprintf("<%T>", arg)
+		} else {
+			formatted[i] = reform.Inspect(arg, true)
+		}
+	}
+	return formatted
+}
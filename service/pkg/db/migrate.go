@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"service/internal/configs"
+	"service/migrations"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/sirupsen/logrus"
+)
+
+// NewMigrator создаёт goose провайдер для встроенных миграций драйвера.
+// В Postgres изменения схемы выполняются под advisory lock, поэтому несколько экземпляров
+// сервиса могут стартовать с автомиграцией одновременно. Файл SQLite принадлежит одному процессу
+// и блокировки не требует.
+// Close у провайдера закрывает db, его вызывает только владелец соединения.
+func NewMigrator(db *sql.DB, driver string) (*goose.Provider, error) {
+	sources, err := fs.Sub(migrations.FS, driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find migrations for driver %q: %w", driver, err)
+	}
+
+	if driver == configs.DriverSQLite {
+		provider, err := goose.NewProvider(goose.DialectSQLite3, db, sources)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create migrator: %w", err)
+		}
+		return provider, nil
+	}
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, sources,
+		goose.WithSessionLocker(locker),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	return provider, nil
+}
+
+func autoMigrate(ctx context.Context, db *sql.DB, driver string, log *logrus.Logger) error {
+	migrator, err := NewMigrator(db, driver)
+	if err != nil {
+		return err
+	}
+
+	results, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed up migrations: %w", err)
+	}
+
+	for _, result := range results {
+		log.WithFields(logrus.Fields{
+			"version":  result.Source.Version,
+			"duration": result.Duration.String(),
+		}).Info("Migration applied")
+	}
+
+	return nil
+}
+
+// CheckMigrations возвращает ошибку, если база отстаёт от последней миграции
+func CheckMigrations(ctx context.Context, migrator *goose.Provider) error {
+	current, target, err := migrator.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get db version: %w", err)
+	}
+
+	if current < target {
+		return fmt.Errorf("database version %d is behind latest migration %d", current, target)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"service/internal/configs"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+)
+
+type primaryKey struct{}
+
+// WithPrimary помечает контекст: чтения в нём идут в основную базу, а не в реплики.
+// Нужно сразу после записи, пока реплики могут ещё не догнать мастер.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryForced сообщает, помечен ли контекст через WithPrimary
+func PrimaryForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryKey{}).(bool)
+	return forced
+}
+
+type replica struct {
+	name    string
+	sqlDB   *sql.DB
+	db      *reform.DB
+	healthy atomic.Bool
+}
+
+// Replicas - пул реплик для чтения с round-robin выбором.
+// Реплика, не ответившая на ping, исключается из выбора до следующей успешной проверки.
+// Нулевой *Replicas допустим и означает, что реплик нет.
+type Replicas struct {
+	replicas []*replica
+	next     atomic.Uint64
+	log      *logrus.Logger
+}
+
+// InitReplicas открывает отдельный пул для каждого DSN из DB_REPLICA_DSNS.
+// Недоступная при старте реплика не мешает запуску: она будет исключена до восстановления.
+func InitReplicas(ctx context.Context, cnf configs.Database, log *logrus.Logger) (*Replicas, error) {
+	replicas := &Replicas{log: log}
+	if len(cnf.ReplicaDSNs) == 0 {
+		return replicas, nil
+	}
+
+	logger := NewQueryLogger(
+		log,
+		cnf.LogMode,
+		time.Duration(cnf.SlowQueryThreshold)*time.Millisecond,
+		cnf.LogRedactArgs,
+	)
+
+	for i, dsn := range cnf.ReplicaDSNs {
+		sqlDB, err := open(dsn, cnf)
+		if err != nil {
+			replicas.Close()
+			return nil, fmt.Errorf("failed to open replica %d: %w", i, err)
+		}
+
+		r := &replica{
+			name:  fmt.Sprintf("replica-%d", i),
+			sqlDB: sqlDB,
+			db:    reform.NewDB(sqlDB, postgresql.Dialect, logger),
+		}
+		replicas.replicas = append(replicas.replicas, r)
+		replicas.check(ctx, r, time.Duration(cnf.ReplicaCheckTimeout)*time.Second)
+	}
+
+	return replicas, nil
+}
+
+// Reader возвращает следующую здоровую реплику или nil, если читать нужно из основной базы
+func (r *Replicas) Reader(ctx context.Context) *reform.DB {
+	if r == nil || len(r.replicas) == 0 || PrimaryForced(ctx) {
+		return nil
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		candidate := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if candidate.healthy.Load() {
+			return candidate.db
+		}
+	}
+
+	return nil
+}
+
+// Watch периодически проверяет реплики до закрытия done
+func (r *Replicas) Watch(done <-chan struct{}, interval, timeout time.Duration) {
+	if r == nil || len(r.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			for _, replica := range r.replicas {
+				r.check(context.Background(), replica, timeout)
+			}
+		}
+	}
+}
+
+func (r *Replicas) check(ctx context.Context, replica *replica, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := replica.sqlDB.PingContext(ctx)
+	healthy := err == nil
+	if replica.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		r.log.WithField("replica", replica.name).Info("Replica is healthy, returned to rotation")
+	} else {
+		r.log.WithError(err).WithField("replica", replica.name).Warn("Replica is unhealthy, ejected from rotation")
+	}
+}
+
+// Pools возвращает пулы соединений реплик по именам, например для метрик
+func (r *Replicas) Pools() map[string]*sql.DB {
+	pools := make(map[string]*sql.DB)
+	if r == nil {
+		return pools
+	}
+	for _, replica := range r.replicas {
+		pools[replica.name] = replica.sqlDB
+	}
+	return pools
+}
+
+func (r *Replicas) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	var firstErr error
+	for _, replica := range r.replicas {
+		if err := replica.sqlDB.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"net/url"
+	"strings"
+)
+
+// sqliteDefaults - настройки соединения SQLite, если они не заданы в DSN явно.
+// WAL не блокирует чтение во время записи, а BEGIN IMMEDIATE берёт блокировку записи в начале
+// транзакции: параллельные изменения ждут друг друга busy_timeout вместо ошибки database is locked.
+var sqliteDefaults = []struct {
+	key     string
+	aliases []string
+	value   string
+}{
+	{key: "_busy_timeout", aliases: []string{"_timeout"}, value: "5000"},
+	{key: "_foreign_keys", aliases: []string{"_fk"}, value: "on"},
+	{key: "_journal_mode", aliases: []string{"_journal"}, value: "WAL"},
+	{key: "_txlock", value: "immediate"},
+}
+
+// sqliteDSN дополняет путь к файлу базы настройками по умолчанию
+func sqliteDSN(dsn string) string {
+	path, rawQuery, _ := strings.Cut(dsn, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// Разбор параметров оставляем драйверу, он вернёт понятную ошибку
+		return dsn
+	}
+
+	for _, d := range sqliteDefaults {
+		if query.Has(d.key) {
+			continue
+		}
+		aliased := false
+		for _, alias := range d.aliases {
+			aliased = aliased || query.Has(alias)
+		}
+		if !aliased {
+			query.Set(d.key, d.value)
+		}
+	}
+
+	return path + "?" + query.Encode()
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто удалять корзины, которые успели заполниться
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// MemoryStore - потокобезопасное хранилище корзин в памяти процесса
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+
+	// Пополняем корзину за прошедшее время
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(capacity, b.tokens+elapsed*limit.Rate)
+	b.updated = now
+
+	result := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = secondsToDuration((capacity - b.tokens) / limit.Rate)
+	b.full = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+// sweep удаляет корзины, которые уже полностью восстановились: они ничем не отличаются от новых
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit - параметры token bucket: Rate токенов в секунду, не больше Burst в корзине
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result - итог попытки взять токен
+type Result struct {
+	Allowed bool
+	// Remaining - сколько токенов осталось в корзине
+	Remaining int
+	// RetryAfter - через сколько появится следующий токен (только если Allowed == false)
+	RetryAfter time.Duration
+	// ResetAfter - через сколько корзина заполнится полностью
+	ResetAfter time.Duration
+}
+
+// Store хранит состояние корзин по ключу клиента.
+// Реализация в памяти считает лимиты в рамках одного процесса;
+// чтобы лимиты действовали на все реплики, нужна реализация поверх общего хранилища.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
@@ -0,0 +1,38 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+const Header = "X-Request-ID"
+
+type ctxKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext возвращает id запроса или пустую строку, если его нет
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// LogrusHook добавляет request_id в записи, созданные через log.WithContext(ctx)
+type LogrusHook struct{}
+
+func (LogrusHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (LogrusHook) Fire(entry *logrus.Entry) error {
+	if id := FromContext(entry.Context); id != "" {
+		entry.Data["request_id"] = id
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package slug делает из заголовка часть URL: латиница в нижнем регистре, цифры и дефисы.
+// Кириллица транслитерируется, у латиницы убираются диакритические знаки, всё остальное - разделитель слов.
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength - предел длины slug в байтах, длинный заголовок обрезается по границе слова
+const MaxLength = 100
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+	// Украинский и белорусский
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+// Make возвращает slug заголовка или пустую строку, если в нём нет ни букв, ни цифр
+func Make(title string) string {
+	var b strings.Builder
+	separate := false
+
+	// NFD раскладывает «é» на «e» и знак ударения, а «й» и «ё» - на «и»/«е» и знак,
+	// поэтому кириллицу проверяем до разложения
+	for _, r := range strings.ToLower(title) {
+		if part, ok := translit[r]; ok {
+			write(&b, part, &separate)
+			continue
+		}
+
+		for _, d := range norm.NFD.String(string(r)) {
+			switch {
+			case d >= 'a' && d <= 'z', d >= '0' && d <= '9':
+				write(&b, string(d), &separate)
+			case unicode.Is(unicode.Mn, d):
+			default:
+				separate = true
+			}
+		}
+	}
+
+	return truncate(b.String())
+}
+
+// write добавляет часть slug, ставя перед ней дефис, если до неё был разделитель слов
+func write(b *strings.Builder, part string, separate *bool) {
+	if part == "" {
+		return
+	}
+	if *separate && b.Len() > 0 {
+		b.WriteByte('-')
+	}
+	*separate = false
+	b.WriteString(part)
+}
+
+func truncate(s string) string {
+	if len(s) <= MaxLength {
+		return s
+	}
+	s = s[:MaxLength]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+	return s
+}
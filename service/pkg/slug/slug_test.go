@@ -0,0 +1,40 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "latin", title: "Hello, World!", want: "hello-world"},
+		{name: "cyrillic", title: "Привет, мир", want: "privet-mir"},
+		{name: "multi-letter transliteration", title: "Щука и жёлтый ёж", want: "shchuka-i-zhyoltyy-yozh"},
+		{name: "short i", title: "Новый год", want: "novyy-god"},
+		{name: "hard and soft signs", title: "Подъезд и соль", want: "podezd-i-sol"},
+		{name: "ukrainian", title: "Їжак і ґанок", want: "yizhak-i-ganok"},
+		{name: "diacritics", title: "Café Über naïve", want: "cafe-uber-naive"},
+		{name: "digits and mixed case", title: "COVID-19: 2025 год", want: "covid-19-2025-god"},
+		{name: "separators collapse", title: "  --a  __ b--  ", want: "a-b"},
+		{name: "no letters", title: "!!! ???", want: ""},
+		{name: "other scripts", title: "東京 news", want: "news"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Make(tt.title))
+		})
+	}
+
+	t.Run("long title is cut on word boundary", func(t *testing.T) {
+		got := Make(strings.Repeat("слово ", 30))
+		assert.LessOrEqual(t, len(got), MaxLength)
+		assert.True(t, strings.HasSuffix(got, "slovo"), got)
+	})
+}
@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogrusHook добавляет trace_id и span_id в записи, созданные через log.WithContext(ctx)
+type LogrusHook struct{}
+
+func (LogrusHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (LogrusHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	spanContext := trace.SpanContextFromContext(entry.Context)
+	if !spanContext.IsValid() {
+		return nil
+	}
+
+	entry.Data["trace_id"] = spanContext.TraceID().String()
+	entry.Data["span_id"] = spanContext.SpanID().String()
+
+	return nil
+}